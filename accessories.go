@@ -1,6 +1,8 @@
 package sqla
 
 import (
+	"context"
+	"log"
 	"runtime"
 	"strconv"
 	"strings"
@@ -12,6 +14,23 @@ func currentFunction() string {
 	return runtime.FuncForPC(counter).Name()
 }
 
+// execRowsAffected executes a statement and returns the number of affected rows.
+// Errors are wrapped with the name of the calling function (fname).
+func execRowsAffected(ctx context.Context, db Executor, fname string, sq string, args []interface{}) (rowsaff int, err error) {
+	if DEBUG {
+		log.Println(sq, args)
+	}
+	res, err := db.ExecContext(ctx, sq, args...)
+	if err != nil {
+		return 0, wrapError(fname, err)
+	}
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return 0, wrapError(fname, err)
+	}
+	return int(ra), nil
+}
+
 func intSlicesEqual(a, b []int) bool {
 	if len(a) != len(b) {
 		return false
@@ -24,6 +43,42 @@ func intSlicesEqual(a, b []int) bool {
 	return true
 }
 
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt(list []int, i int) bool {
+	for _, v := range list {
+		if v == i {
+			return true
+		}
+	}
+	return false
+}
+
+func nonEmptyStrings(list []string) (res []string) {
+	for _, v := range list {
+		if v != "" {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
 func isStringASCII(s string) bool {
 	for _, r := range s {
 		if r > unicode.MaxASCII {
@@ -34,6 +34,27 @@ func (a AnyTslice) AppendInt64(column string, i int64) AnyTslice {
 	return a
 }
 
+// AppendBool appends bool to AnyTslice
+func (a AnyTslice) AppendBool(column string, b bool) AnyTslice {
+	const B = 1
+	a = append(a, anyT{c: column, t: B, b: b})
+	return a
+}
+
+// AppendFloat64 appends float64 to AnyTslice
+func (a AnyTslice) AppendFloat64(column string, f float64) AnyTslice {
+	const F = 2
+	a = append(a, anyT{c: column, t: F, f: f})
+	return a
+}
+
+// AppendString appends string to AnyTslice even if it is empty.
+func (a AnyTslice) AppendString(column string, s string) AnyTslice {
+	const S = 3
+	a = append(a, anyT{c: column, t: S, s: s})
+	return a
+}
+
 // AppendNil appends nil to AnyTslice
 func (a AnyTslice) AppendNil(column string) AnyTslice {
 	const N = 4
@@ -1,49 +1,38 @@
 package sqla
 
 import (
-	"strconv"
 	"strings"
 )
 
 // MakeParam takes database type (see constants) and parameter positional number to create ordial (or positional) placeholder for a parameter in your SQL statement.
 // E.g. for the parameter #1 they are: $1 for SQLITE, @p1 for MSSQL, ? for MYSQL, :1 for ORACLE, $1 for POSTGRESQL. The function return only this placeholder.
+// Placeholders are defined by Dialect registered for the database type.
 func MakeParam(DBType byte, argsCounter int) string {
-	switch DBType {
-	case SQLITE:
-		return "$" + strconv.Itoa(argsCounter)
-	case MSSQL:
-		return "@p" + strconv.Itoa(argsCounter)
-	case MYSQL:
-		return "?"
-	case ORACLE:
-		return ":" + strconv.Itoa(argsCounter)
-	case POSTGRESQL:
-		return "$" + strconv.Itoa(argsCounter)
-	}
-	return "?"
+	return GetDialect(DBType).Placeholder(argsCounter)
 }
 
 // BuildSQLIN makes and adds a part of SQL statement with all numbered parameters supplied in a valueList.
 // sq argument should be complete SQL statement, as BuildSQLIN returns augmented statement, not part of it. argsCounter is required to define from what number to start count positional parameters.
 // The added part of a statement will look something like 'WHERE/AND column IN(a list of placeholders by MakeParam func, e.g. $1, $2)'.
 // BuildSQLIN returns counter as the number of added parameters for use in other routine and args as []interface{} of payload arguments which may be supplied to Go sql functions.
+// See BuildSQLINOf for empty and long lists.
 func BuildSQLIN(DBType byte, sq string, argsCounter int, column string, valueList []int) (counter int, resquery string, args []interface{}) {
+	return BuildSQLINOf(DBType, sq, argsCounter, column, valueList)
+}
+
+// BuildSQLINOf does the same as BuildSQLIN, but takes a list of values of any comparable type, e.g. []string, []int64,
+// or UUID values implementing driver.Valuer which convert themselves to the form accepted by a database.
+// If valueList is empty, the false predicate '1 = 0' is added instead of invalid 'IN ()'.
+// If valueList is longer than the dialect allows for one IN list (see Dialect.MaxInList), it is split: '(column IN(...) OR column IN(...))'.
+func BuildSQLINOf[T comparable](DBType byte, sq string, argsCounter int, column string, valueList []T) (counter int, resquery string, args []interface{}) {
 	if strings.Contains(sq, "WHERE") {
 		sq += "AND "
 	} else {
 		sq += "WHERE "
 	}
-	sq += column + " IN ("
-	for i := 0; i < len(valueList); i++ {
-		argsCounter++
-		if i == 0 {
-			sq += MakeParam(DBType, argsCounter)
-		} else {
-			sq += ", " + MakeParam(DBType, argsCounter)
-		}
-		args = append(args, valueList[i])
-	}
-	sq += ") "
+	var part string
+	argsCounter, part, args = buildINList(DBType, argsCounter, column, " IN (", " OR ", "1 = 0", valueList)
+	sq += part
 	counter = argsCounter
 	resquery = sq
 	return counter, resquery, args
@@ -53,23 +42,22 @@ func BuildSQLIN(DBType byte, sq string, argsCounter int, column string, valueLis
 // sq argument should be complete SQL statement, as BuildSQLINNOT returns augmented statement, not part of it. argsCounter is required to define from what number to start count positional parameters.
 // The added part of a statement will look something like 'WHERE/AND column NOT IN(a list of placeholders by MakeParam func, e.g. $1, $2)'.
 // BuildSQLINNOT returns counter as the number of added parameters for use in other routine, statement, and args as []interface{} of payload arguments which may be supplied to Go sql functions.
+// See BuildSQLINNOTOf for empty and long lists.
 func BuildSQLINNOT(DBType byte, sq string, argsCounter int, column string, valueList []int) (counter int, resquery string, args []interface{}) {
+	return BuildSQLINNOTOf(DBType, sq, argsCounter, column, valueList)
+}
+
+// BuildSQLINNOTOf does the same as BuildSQLINNOT, but takes a list of values of any comparable type.
+// If valueList is empty, the true predicate '1 = 1' is added. Long lists are split: '(column NOT IN(...) AND column NOT IN(...))'.
+func BuildSQLINNOTOf[T comparable](DBType byte, sq string, argsCounter int, column string, valueList []T) (counter int, resquery string, args []interface{}) {
 	if strings.Contains(sq, "WHERE") {
 		sq += "AND "
 	} else {
 		sq += "WHERE "
 	}
-	sq += column + " NOT IN ("
-	for i := 0; i < len(valueList); i++ {
-		argsCounter++
-		if i == 0 {
-			sq += MakeParam(DBType, argsCounter)
-		} else {
-			sq += ", " + MakeParam(DBType, argsCounter)
-		}
-		args = append(args, valueList[i])
-	}
-	sq += ") "
+	var part string
+	argsCounter, part, args = buildINList(DBType, argsCounter, column, " NOT IN (", " AND ", "1 = 1", valueList)
+	sq += part
 	counter = argsCounter
 	resquery = sq
 	return counter, resquery, args
@@ -80,7 +68,14 @@ func BuildSQLINNOT(DBType byte, sq string, argsCounter int, column string, value
 // The added part of a statement will look something like 'WHERE/AND (column IN($1, $2) OR another_column IN($1, $2), etc...)'.
 // Although the 'WHERE/AND (' part will be added only when FirstIter argument is true and the closing ')' will be added only when LastIter argument is true.
 // BuildSQLINOR returns counter as the number of added parameters for use in other routine, statement, and args as []interface{} of payload arguments which may be supplied to Go sql functions.
+// See BuildSQLINOROf for empty and long lists.
 func BuildSQLINOR(DBType byte, sq string, argsCounter int, column string, valueList []int, FirstIter bool, LastIter bool) (counter int, resquery string, args []interface{}) {
+	return BuildSQLINOROf(DBType, sq, argsCounter, column, valueList, FirstIter, LastIter)
+}
+
+// BuildSQLINOROf does the same as BuildSQLINOR, but takes a list of values of any comparable type.
+// Empty and long lists are handled the same way as in BuildSQLINOf.
+func BuildSQLINOROf[T comparable](DBType byte, sq string, argsCounter int, column string, valueList []T, FirstIter bool, LastIter bool) (counter int, resquery string, args []interface{}) {
 	if FirstIter {
 		if strings.Contains(sq, "WHERE") {
 			sq += "AND ("
@@ -91,17 +86,9 @@ func BuildSQLINOR(DBType byte, sq string, argsCounter int, column string, valueL
 	if !FirstIter {
 		sq += "OR "
 	}
-	sq += column + " IN ("
-	for i := 0; i < len(valueList); i++ {
-		argsCounter++
-		if i == 0 {
-			sq += MakeParam(DBType, argsCounter)
-		} else {
-			sq += ", " + MakeParam(DBType, argsCounter)
-		}
-		args = append(args, valueList[i])
-	}
-	sq += ") "
+	var part string
+	argsCounter, part, args = buildINList(DBType, argsCounter, column, " IN (", " OR ", "1 = 0", valueList)
+	sq += part
 	if LastIter {
 		sq += ") "
 	}
@@ -109,3 +96,54 @@ func BuildSQLINOR(DBType byte, sq string, argsCounter int, column string, valueL
 	resquery = sq
 	return counter, resquery, args
 }
+
+// buildINList makes 'column IN ($1, $2) ' part, where operator is " IN (" or " NOT IN (".
+// The list is split into several parts joined with joiner if it is longer than Dialect.MaxInList, and empty list is replaced with emptyPredicate.
+func buildINList[T comparable](DBType byte, argsCounter int, column string, operator string, joiner string, emptyPredicate string, valueList []T) (counter int, part string, args []interface{}) {
+	if len(valueList) == 0 {
+		return argsCounter, emptyPredicate + " ", nil
+	}
+	size := GetDialect(DBType).MaxInList()
+	if size <= 0 || size > len(valueList) {
+		size = len(valueList)
+	}
+	var lists []string
+	for start := 0; start < len(valueList); start += size {
+		list := column + operator
+		for i := start; i < start+size && i < len(valueList); i++ {
+			argsCounter++
+			if i == start {
+				list += MakeParam(DBType, argsCounter)
+			} else {
+				list += ", " + MakeParam(DBType, argsCounter)
+			}
+			args = append(args, valueList[i])
+		}
+		lists = append(lists, list+")")
+	}
+	if len(lists) == 1 {
+		return argsCounter, lists[0] + " ", args
+	}
+	return argsCounter, "(" + strings.Join(lists, joiner) + ") ", args
+}
+
+// chunkValues splits valueList into parts short enough to execute a statement with each of them without exceeding Dialect.MaxParams.
+// reserved is the number of other parameters of the statement.
+func chunkValues[T any](DBType byte, valueList []T, reserved int) [][]T {
+	size := GetDialect(DBType).MaxParams() - reserved
+	if GetDialect(DBType).MaxParams() <= 0 || size >= len(valueList) {
+		return [][]T{valueList}
+	}
+	if size < 1 {
+		size = 1
+	}
+	var chunks [][]T
+	for start := 0; start < len(valueList); start += size {
+		end := start + size
+		if end > len(valueList) {
+			end = len(valueList)
+		}
+		chunks = append(chunks, valueList[start:end])
+	}
+	return chunks
+}
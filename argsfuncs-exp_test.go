@@ -0,0 +1,78 @@
+package sqla
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+)
+
+type smallSQLiteDialect struct {
+	SQLiteDialect
+}
+
+func (smallSQLiteDialect) MaxParams() int { return 2 }
+
+func TestBuildSQLINOf(t *testing.T) {
+	_, sq, args := BuildSQLINOf(POSTGRESQL, "SELECT * FROM users ", 1, "Email", []string{"a@example.com", "b@example.com"})
+	expected := "SELECT * FROM users WHERE Email IN ($2, $3) "
+	if sq != expected || len(args) != 2 {
+		t.Errorf("Expected:%s, received:%s, %v", expected, sq, args)
+	}
+
+	counter, sq, args := BuildSQLINOf(POSTGRESQL, "SELECT * FROM users ", 0, "Status", []string{})
+	expected = "SELECT * FROM users WHERE 1 = 0 "
+	if sq != expected || counter != 0 || len(args) != 0 {
+		t.Errorf("Expected:%s, received:%s, %v", expected, sq, args)
+	}
+	_, sq, _ = BuildSQLINNOTOf(MSSQL, "SELECT * FROM users WHERE ID > 0 ", 0, "Status", []int64{})
+	expected = "SELECT * FROM users WHERE ID > 0 AND 1 = 1 "
+	if sq != expected {
+		t.Errorf("Expected:%s, received:%s", expected, sq)
+	}
+	_, sq, _ = BuildSQLINOROf(SQLITE, "SELECT * FROM users ", 0, "Status", []string{}, true, false)
+	_, sq, _ = BuildSQLINOROf(SQLITE, sq, 0, "Role", []string{"admin"}, false, true)
+	expected = "SELECT * FROM users WHERE (1 = 0 OR Role IN ($1) ) "
+	if sq != expected {
+		t.Errorf("Expected:%s, received:%s", expected, sq)
+	}
+
+	ids := make([]int64, 2500)
+	for i := range ids {
+		ids[i] = int64(i)
+	}
+	counter, sq, args = BuildSQLINOf(ORACLE, "SELECT * FROM users ", 0, "ID", ids)
+	if counter != 2500 || len(args) != 2500 || strings.Count(sq, "ID IN (") != 3 || !strings.HasPrefix(sq, "SELECT * FROM users WHERE (ID IN (:1, ") {
+		t.Errorf("Expected 3 IN lists and 2500 args, received:%d, %d, %s", strings.Count(sq, "ID IN ("), len(args), sq[:60])
+	}
+	_, sq, _ = BuildSQLINNOTOf(ORACLE, "SELECT * FROM users ", 0, "ID", ids)
+	if strings.Count(sq, ") AND ID NOT IN (") != 2 {
+		t.Errorf("Expected NOT IN lists joined with AND, received:%s", sq[:60])
+	}
+
+	chunks := chunkValues(MSSQL, ids, 100)
+	if len(chunks) != 2 || len(chunks[0]) != 2000 || len(chunks[1]) != 500 {
+		t.Errorf("Expected:%d, received:%d", 2, len(chunks))
+	}
+}
+
+func TestDeleteObjectsChunks(t *testing.T) {
+	const SMALLDB = 101
+	RegisterDialect(SMALLDB, smallSQLiteDialect{})
+	var db *sql.DB
+	db = OpenSQLConnection(SQLITE, "file:chunkstest?mode=memory&cache=shared")
+	defer db.Close()
+	db.Exec("CREATE TABLE tags (ID INTEGER PRIMARY KEY, Name TEXT);")
+	var ids []int
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		var args AnyTslice
+		args = args.AppendNonEmptyString("Name", name)
+		lastid, _ := InsertObject(db, SQLITE, "tags", args)
+		ids = append(ids, lastid)
+	}
+	if rowsaff := SetToNull(db, SMALLDB, "tags", "ID", []int{}); rowsaff != 0 {
+		t.Errorf("Expected:%d, received:%d", 0, rowsaff)
+	}
+	if rowsaff := DeleteObjects(db, SMALLDB, "tags", "ID", ids); rowsaff != 5 {
+		t.Errorf("Expected:%d, received:%d", 5, rowsaff)
+	}
+}
@@ -1,6 +1,7 @@
 package sqla
 
 import (
+	"encoding/json"
 	"strconv"
 	"strings"
 )
@@ -58,6 +59,10 @@ func buildSQLTXTSearch(DBType byte, sq string, argsCounter int, operator string,
 	val = "%" + val + "%"
 	argsCounter++
 	args = append(args, val)
+	if collation := GetDialect(DBType).LikeCollation(); caseInsensitive && collation != "" {
+		suffix += collation
+		caseInsensitive = false
+	}
 	var valUpper string // will hold UPPERCASE string
 	var valLower string // will hold lowercase string
 	var valFirst string // will hold Firstletterupper string
@@ -116,8 +121,8 @@ func buildUncountedSQLTXTSearch(DBType byte, sq string, argsCounter int, operato
 			sq += "WHERE ("
 		}
 		sq += columns[i] + operator + MakeParam(DBType, argsCounter) + suffix
-		if DBType == ORACLE && caseInsensitive {
-			sq += "COLLATE binary_ai "
+		if caseInsensitive {
+			sq += GetDialect(DBType).LikeCollation()
 		}
 		args = append(args, val)
 	}
@@ -238,3 +243,100 @@ func buildSQLintBETWEEN(DBType byte, sq string, argsCounter int, column string,
 	resquery = sq
 	return counter, resquery, args
 }
+
+// buildStringClassFilter adds to sq 'WHERE/AND column IN ($1, $2) ' or, in InJSON mode, 'WHERE/AND (column LIKE $1 ESCAPE '!' OR ...) ' condition of StringClassFilter.
+func buildStringClassFilter(DBType byte, sq string, argsCounter int, FC StringClassFilter) (counter int, resquery string, args []interface{}) {
+	if strings.Contains(sq, "WHERE") {
+		sq += "AND "
+	} else {
+		sq += "WHERE "
+	}
+	var part string
+	argsCounter, part, args = stringClassCondition(DBType, argsCounter, FC)
+	return argsCounter, sq + part, args
+}
+
+// buildStringClassFilterOR does the same as buildStringClassFilter, but joins conditions with OR in parentheses like BuildSQLINOR does.
+func buildStringClassFilterOR(DBType byte, sq string, argsCounter int, FC StringClassFilter, FirstIter bool, LastIter bool) (counter int, resquery string, args []interface{}) {
+	if FirstIter {
+		if strings.Contains(sq, "WHERE") {
+			sq += "AND ("
+		} else {
+			sq += "WHERE ("
+		}
+	} else {
+		sq += "OR "
+	}
+	var part string
+	argsCounter, part, args = stringClassCondition(DBType, argsCounter, FC)
+	sq += part
+	if LastIter {
+		sq += ") "
+	}
+	return argsCounter, sq, args
+}
+
+// stringClassCondition makes condition of StringClassFilter. Case-insensitive condition compares UPPER(column) with upper-case values
+// unless the database compares strings case-insensitively. InJSON condition searches for JSON-encoded values as AppendJSONList writes them.
+func stringClassCondition(DBType byte, argsCounter int, FC StringClassFilter) (counter int, part string, args []interface{}) {
+	column := FC.Column
+	upper := FC.CaseInsensitive && !GetDialect(DBType).CaseInsensitiveEquality()
+	if upper {
+		column = "UPPER(" + column + ")"
+	}
+	if !FC.InJSON {
+		list := FC.List
+		if upper {
+			list = make([]string, len(FC.List))
+			for i, v := range FC.List {
+				list[i] = strings.ToUpper(v)
+			}
+		}
+		return buildINList(DBType, argsCounter, column, " IN (", " OR ", "1 = 0", list)
+	}
+	if len(FC.List) == 0 {
+		return argsCounter, "1 = 0 ", nil
+	}
+	var conditions []string
+	for _, v := range FC.List {
+		jsonValue, _ := json.Marshal(v)
+		val := string(jsonValue)
+		// ! is used as escape character, as backslash escapes the closing quote of ESCAPE '\' in MySQL
+		val = strings.Replace(val, "!", "!!", -1)
+		val = strings.Replace(val, "%", "!%", -1)
+		val = strings.Replace(val, "_", "!_", -1)
+		if upper {
+			val = strings.ToUpper(val)
+		}
+		argsCounter++
+		conditions = append(conditions, column+" LIKE "+MakeParam(DBType, argsCounter)+" ESCAPE '!'")
+		args = append(args, "%"+val+"%")
+	}
+	return argsCounter, "(" + strings.Join(conditions, " OR ") + ") ", args
+}
+
+// buildNullFilter adds to sq 'WHERE/AND column IS NULL' or 'WHERE/AND column IS NOT NULL' condition of NullFilter,
+// in InJSON mode empty JSON list is treated as NULL: 'WHERE/AND (column IS NULL OR column = '[]')'.
+func buildNullFilter(DBType byte, sq string, argsCounter int, NF NullFilter) (counter int, resquery string, args []interface{}) {
+	if strings.Contains(sq, "WHERE") {
+		sq += "AND "
+	} else {
+		sq += "WHERE "
+	}
+	switch {
+	case NF.Null && NF.InJSON:
+		sq += "(" + NF.Column + " IS NULL OR " + NF.Column + " = '[]') "
+	case NF.Null:
+		sq += NF.Column + " IS NULL "
+	case NF.InJSON:
+		sq += "(" + NF.Column + " IS NOT NULL AND " + NF.Column + " <> '[]') "
+	default:
+		sq += NF.Column + " IS NOT NULL "
+	}
+	return argsCounter, sq, nil
+}
+
+// buildBoolFilter adds to sq 'WHERE/AND column = $1' condition of BoolFilter with the value for the database.
+func buildBoolFilter(DBType byte, sq string, argsCounter int, BF BoolFilter) (counter int, resquery string, args []interface{}) {
+	return buildSQLCOMPARE(DBType, sq, argsCounter, BF.Column, " = ", BoolValue(DBType, BF.Value))
+}
@@ -0,0 +1,283 @@
+package sqla
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// AuditTable is the name of the table where audit records are written, see TableMeta.Audit and CreateAuditTable.
+var AuditTable = "sqla_audit"
+
+// Operations written to audit records.
+const (
+	AuditInsert = "insert"
+	AuditUpdate = "update"
+	AuditDelete = "delete"
+)
+
+// AuditRecord is a record of audit table describing one change of an object.
+// ObjectKey contains key values of the object, comma-separated for composite keys. Actor is the one set by WithActor.
+// ChangedAt is Unix time in nanoseconds. Before contains column values read before update or delete, After contains values written by insert or update.
+type AuditRecord struct {
+	ID        int64
+	TableName string
+	ObjectKey string
+	Operation string
+	Actor     string
+	ChangedAt int64
+	Before    map[string]interface{} `sqla:"BeforeData,json"`
+	After     map[string]interface{} `sqla:"AfterData,json"`
+}
+
+// ErrAuditNotSupported is returned by the functions which cannot write audit records (CopyIn) if audit is enabled for the table.
+var ErrAuditNotSupported = errors.New("function does not support audit of the table")
+
+type actorKey struct{}
+
+// WithActor returns a copy of ctx carrying actor (e.g. user ID) to write into audit records by the functions which take this context.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns actor set by WithActor, or empty string if there is none.
+func ActorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
+
+// CreateAuditTable creates audit table (see AuditTable) for the database type if it does not exist.
+// Any error is only logged, use CreateAuditTableContext to receive it.
+func CreateAuditTable(db Executor, DBType byte) {
+	if err := CreateAuditTableContext(context.Background(), db, DBType); err != nil {
+		log.Println(err)
+	}
+}
+
+// CreateAuditTableContext does the same as CreateAuditTable, but it takes a context and returns an error instead of logging it.
+func CreateAuditTableContext(ctx context.Context, db Executor, DBType byte) error {
+	id, text, data, bigint := "ID BIGINT PRIMARY KEY", "VARCHAR(255)", "TEXT", "BIGINT"
+	ifNotExists := "IF NOT EXISTS "
+	switch DBType {
+	case SQLITE:
+		id = "ID INTEGER PRIMARY KEY"
+	case MSSQL:
+		id, text, data, ifNotExists = "ID BIGINT IDENTITY PRIMARY KEY", "NVARCHAR(255)", "NVARCHAR(MAX)", ""
+	case MYSQL, MARIADB:
+		id, data = "ID BIGINT AUTO_INCREMENT PRIMARY KEY", "LONGTEXT"
+	case ORACLE:
+		id, text, data, bigint, ifNotExists = "ID NUMBER GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY", "VARCHAR2(255)", "CLOB", "NUMBER(19)", ""
+	case POSTGRESQL, COCKROACHDB:
+		id = "ID BIGSERIAL PRIMARY KEY"
+	case DUCKDB:
+		sq := "CREATE SEQUENCE IF NOT EXISTS " + AuditTable + "_seq"
+		if _, err := db.ExecContext(ctx, sq); err != nil {
+			return wrapError(currentFunction(), err)
+		}
+		id = "ID BIGINT PRIMARY KEY DEFAULT nextval('" + AuditTable + "_seq')"
+	default:
+		return fmt.Errorf("%s: unknown database type %d", currentFunction(), DBType)
+	}
+	sq := "CREATE TABLE " + ifNotExists + AuditTable + " (" + id + ", TableName " + text + " NOT NULL, ObjectKey " + text + ", Operation " + text + " NOT NULL, Actor " + text +
+		", ChangedAt " + bigint + " NOT NULL, BeforeData " + data + ", AfterData " + data + ")"
+	if ifNotExists == "" {
+		if DBType == MSSQL {
+			sq = "IF OBJECT_ID('" + AuditTable + "', 'U') IS NULL " + sq
+		} else {
+			sq = "BEGIN EXECUTE IMMEDIATE '" + sq + "'; EXCEPTION WHEN OTHERS THEN IF SQLCODE != -955 THEN RAISE; END IF; END;"
+		}
+	}
+	if DEBUG {
+		log.Println(sq)
+	}
+	if _, err := db.ExecContext(ctx, sq); err != nil {
+		return wrapError(currentFunction(), err)
+	}
+	return nil
+}
+
+// GetAuditHistory returns audit records of one object of the table ordered by time of change, key contains key values of the object.
+// Any error is only logged, use GetAuditHistoryContext to receive it.
+func GetAuditHistory(db Executor, DBType byte, table string, key ...interface{}) (records []AuditRecord) {
+	records, err := GetAuditHistoryContext(context.Background(), db, DBType, table, key...)
+	if err != nil {
+		log.Println(err)
+	}
+	return records
+}
+
+// GetAuditHistoryContext does the same as GetAuditHistory, but it takes a context and returns an error instead of logging it.
+func GetAuditHistoryContext(ctx context.Context, db Executor, DBType byte, table string, key ...interface{}) (records []AuditRecord, err error) {
+	sq := "SELECT ID, TableName, ObjectKey, Operation, Actor, ChangedAt, BeforeData, AfterData FROM " + AuditTable +
+		" WHERE TableName = " + MakeParam(DBType, 1) + " AND ObjectKey = " + MakeParam(DBType, 2) + " ORDER BY ChangedAt, ID"
+	if err = SelectIntoContext(ctx, db, &records, sq, table, auditKey(key)); err != nil {
+		return nil, fmt.Errorf("%s: %w", currentFunction(), err)
+	}
+	return records, nil
+}
+
+// auditInTx runs fn in a transaction if audit is enabled for the table and db is *sql.DB, so changes and audit records are written together.
+// It reports whether fn was run, if not the caller should make changes using db as usual.
+func auditInTx(ctx context.Context, db Executor, meta TableMeta, fn func(tx *sql.Tx) error) (bool, error) {
+	sqldb, ok := db.(*sql.DB)
+	if !meta.Audit || !ok {
+		return false, nil
+	}
+	return true, WithTxContext(ctx, sqldb, nil, fn)
+}
+
+// execAudited executes statement sq with the condition added by where, and if audit is enabled for the table, writes audit records of the operation
+// for the rows matching the condition, which are read before the statement. after contains the values set by update statement, nil for delete.
+func (m TableMeta) execAudited(ctx context.Context, db Executor, DBType byte, fname string, operation string, argsCounter int, sq string, args []interface{}, after map[string]interface{},
+	where func(sq string, argsCounter int) (int, string, []interface{}, error)) (rowsaff int, err error) {
+	_, sq, argstoAppend, err := where(sq, argsCounter)
+	if err != nil {
+		return 0, wrapError(fname, err)
+	}
+	args = append(args, argstoAppend...)
+
+	var before []map[string]interface{}
+	if m.Audit {
+		_, selsq, selargs, _ := where("SELECT * FROM "+m.Name+" ", 0)
+		if before, err = readRows(ctx, db, selsq, selargs); err != nil {
+			return 0, wrapError(fname, err)
+		}
+	}
+
+	rowsaff, err = execRowsAffected(ctx, db, fname, sq, args)
+	if err == nil && m.Audit && rowsaff > 0 {
+		if err = writeAuditRows(ctx, db, DBType, m, operation, before, after); err != nil {
+			return 0, wrapError(fname, err)
+		}
+	}
+	return rowsaff, err
+}
+
+// writeAudit writes an audit record of the operation with an object of the table.
+func writeAudit(ctx context.Context, db Executor, DBType byte, table string, operation string, key string, before map[string]interface{}, after map[string]interface{}) error {
+	var args []interface{}
+	args = append(args, table, key, operation, ActorFromContext(ctx), time.Now().UnixNano())
+	for _, data := range []map[string]interface{}{before, after} {
+		if data == nil {
+			args = append(args, nil)
+			continue
+		}
+		JSON, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+		args = append(args, string(JSON))
+	}
+	var values []string
+	for i := range args {
+		values = append(values, MakeParam(DBType, i+1))
+	}
+	sq := "INSERT INTO " + AuditTable + " (TableName, ObjectKey, Operation, Actor, ChangedAt, BeforeData, AfterData) VALUES (" + strings.Join(values, ", ") + ")"
+	_, err := execRowsAffected(ctx, db, currentFunction(), sq, args)
+	return err
+}
+
+// writeAuditRows writes audit records of the operation for rows read by readRows, the object key is taken from key columns of the rows.
+func writeAuditRows(ctx context.Context, db Executor, DBType byte, meta TableMeta, operation string, rows []map[string]interface{}, after map[string]interface{}) error {
+	for _, row := range rows {
+		var key []interface{}
+		for _, col := range meta.Key {
+			for c, v := range row {
+				if strings.EqualFold(c, col) {
+					key = append(key, v)
+				}
+			}
+		}
+		if err := writeAudit(ctx, db, DBType, meta.Name, operation, auditKey(key), row, after); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readRows selects rows to write their values into audit records.
+func readRows(ctx context.Context, db Executor, sq string, args []interface{}) (result []map[string]interface{}, err error) {
+	if DEBUG {
+		log.Println(sq, args)
+	}
+	rows, err := db.QueryContext(ctx, sq, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err = rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+		row := map[string]interface{}{}
+		for i, col := range columns {
+			if b, ok := values[i].([]byte); ok {
+				values[i] = string(b)
+			}
+			row[col] = values[i]
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+// anyTMap returns column values of iargs to write them into audit records.
+func anyTMap(iargs []anyT) map[string]interface{} {
+	m := map[string]interface{}{}
+	for _, v := range iargs {
+		m[v.c] = argValue(v)
+	}
+	return m
+}
+
+// auditKey makes ObjectKey of audit record from key values, see keyValue.
+func auditKey(key []interface{}) string {
+	var parts []string
+	for _, k := range key {
+		k = keyValue(k)
+		if b, ok := k.([]byte); ok {
+			k = string(b)
+		}
+		if k == nil {
+			parts = append(parts, "")
+			continue
+		}
+		parts = append(parts, fmt.Sprint(k))
+	}
+	return strings.Join(parts, ",")
+}
+
+// keyValue returns the value of key which may be a pointer (e.g. key destination of InsertObjectKey) or driver.Valuer.
+func keyValue(k interface{}) interface{} {
+	v := reflect.ValueOf(k)
+	for v.IsValid() && v.Kind() == reflect.Ptr && !v.IsNil() && !v.Type().Implements(valuerType) {
+		v = v.Elem()
+	}
+	if v.IsValid() {
+		k = v.Interface()
+	}
+	if valuer, ok := k.(driver.Valuer); ok {
+		val, err := valuer.Value()
+		if err != nil {
+			return nil
+		}
+		return val
+	}
+	return k
+}
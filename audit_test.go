@@ -0,0 +1,190 @@
+package sqla
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestAudit(t *testing.T) {
+	var DBType byte
+	DBType = SQLITE
+	var db *sql.DB
+	db = OpenSQLConnection(DBType, "file:audittest?mode=memory&cache=shared")
+	defer db.Close()
+	db.Exec("CREATE TABLE invoices (ID INTEGER PRIMARY KEY, Number TEXT NOT NULL, Total INTEGER);")
+	if err := CreateAuditTableContext(context.Background(), db, DBType); err != nil {
+		t.Fatal(err)
+	}
+	CreateAuditTable(db, DBType)
+	RegisterTable(TableMeta{Name: "invoices", Audit: true})
+	ctx := WithActor(context.Background(), "42")
+
+	var args AnyTslice
+	args = args.AppendNonEmptyString("Number", "INV-1")
+	args = args.AppendInt("Total", 100)
+	ID, _, err := InsertObjectContext(ctx, db, DBType, "invoices", args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	args = nil
+	args = args.AppendInt("Total", 150)
+	if _, err = UpdateObjectContext(ctx, db, DBType, "invoices", args, ID); err != nil {
+		t.Fatal(err)
+	}
+	args = nil
+	args = args.AppendNil("Number")
+	if _, err = UpdateObjectContext(ctx, db, DBType, "invoices", args, ID); err == nil {
+		t.Errorf("Expected an error on NOT NULL constraint, received nil")
+	}
+	if _, err = DeleteObjectsContext(ctx, db, DBType, "invoices", "ID", []int{ID}); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := GetAuditHistoryContext(context.Background(), db, DBType, "invoices", ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("Expected:%d, received:%d", 3, len(records))
+	}
+	operations := []string{AuditInsert, AuditUpdate, AuditDelete}
+	for i, r := range records {
+		if r.Operation != operations[i] || r.Actor != "42" || r.ObjectKey != strconv.Itoa(ID) || r.ChangedAt == 0 {
+			t.Errorf("Expected:%s by %s, received:%+v", operations[i], "42", r)
+		}
+	}
+	if records[0].Before != nil || records[0].After["Number"] != "INV-1" {
+		t.Errorf("Expected:%s, received:%v", "INV-1", records[0].After)
+	}
+	if records[1].Before["Total"] != float64(100) || records[1].After["Total"] != float64(150) {
+		t.Errorf("Expected:%d -> %d, received:%v -> %v", 100, 150, records[1].Before["Total"], records[1].After["Total"])
+	}
+	if records[2].Before["Total"] != float64(150) || records[2].After != nil {
+		t.Errorf("Expected:%d, received:%v", 150, records[2].Before)
+	}
+}
+
+func TestAuditOtherFunctions(t *testing.T) {
+	var DBType byte
+	DBType = SQLITE
+	var db *sql.DB
+	db = OpenSQLConnection(DBType, "file:auditothertest?mode=memory&cache=shared")
+	defer db.Close()
+	db.Exec("CREATE TABLE issues (ID INTEGER PRIMARY KEY, Status INTEGER, Changed INTEGER, Owner INTEGER, Deleted INTEGER);")
+	if err := CreateAuditTableContext(context.Background(), db, DBType); err != nil {
+		t.Fatal(err)
+	}
+	RegisterTable(TableMeta{Name: "issues", Audit: true, SoftDeleteColumn: "Deleted"})
+	ctx := WithActor(context.Background(), "7")
+
+	var args AnyTslice
+	args = args.AppendInt("Status", 1)
+	args = args.AppendInt("Owner", 5)
+	ID, _, err := InsertObjectContext(ctx, db, DBType, "issues", args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = UpdateMultipleWithOneIntContext(ctx, db, DBType, "issues", "Status", 2, "Changed", 1000, []int{ID}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = SetToNullContext(ctx, db, DBType, "issues", "Owner", []int{5}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = DeleteObjectContext(ctx, db, DBType, "issues", "ID", ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = RestoreObjectsContext(ctx, db, DBType, "issues", []int{ID}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = DeleteObjectContext(ctx, db, DBType, "issues", "ID", ID); err != nil {
+		t.Fatal(err)
+	}
+	if rowsaff, err := PurgeDeletedContext(ctx, db, DBType, "issues", time.Now().Add(time.Second)); err != nil || rowsaff != 1 {
+		t.Fatalf("Expected:%d, received:%d, %v", 1, rowsaff, err)
+	}
+
+	records, err := GetAuditHistoryContext(context.Background(), db, DBType, "issues", ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	operations := []string{AuditInsert, AuditUpdate, AuditUpdate, AuditDelete, AuditUpdate, AuditDelete, AuditDelete}
+	if len(records) != len(operations) {
+		t.Fatalf("Expected:%d, received:%d", len(operations), len(records))
+	}
+	for i, r := range records {
+		if r.Operation != operations[i] || r.Actor != "7" {
+			t.Errorf("Expected:%s by %s, received:%+v", operations[i], "7", r)
+		}
+	}
+	if records[1].Before["Status"] != float64(1) || records[1].After["Status"] != float64(2) || records[1].After["Changed"] != float64(1000) {
+		t.Errorf("Expected:%d -> %d, received:%v -> %v", 1, 2, records[1].Before, records[1].After)
+	}
+	if records[2].Before["Owner"] != float64(5) || records[2].After["Owner"] != nil {
+		t.Errorf("Expected:%d -> NULL, received:%v -> %v", 5, records[2].Before, records[2].After)
+	}
+	if records[4].Before["Deleted"] == nil || records[4].After["Deleted"] != nil {
+		t.Errorf("Expected:deleted -> NULL, received:%v -> %v", records[4].Before, records[4].After)
+	}
+	if records[6].Before["Deleted"] == nil || records[6].After != nil {
+		t.Errorf("Expected:purged deleted row, received:%v -> %v", records[6].Before, records[6].After)
+	}
+
+	var row1, row2 AnyTslice
+	row1 = row1.AppendInt("Status", 1)
+	row2 = row2.AppendInt("Status", 2)
+	rows := []AnyTslice{row1, row2}
+	ids, rowsaff, err := InsertObjectsContext(ctx, db, DBType, "issues", rows, false)
+	if err != nil || rowsaff != 2 || len(ids) != 2 {
+		t.Fatalf("Expected:%d, received:%d, %v, %v", 2, rowsaff, ids, err)
+	}
+	for i, id := range ids {
+		records, err = GetAuditHistoryContext(context.Background(), db, DBType, "issues", id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(records) == 0 {
+			t.Fatalf("Expected:%s, received:%+v", AuditInsert, records)
+		}
+		if r := records[len(records)-1]; r.Operation != AuditInsert || r.Before != nil || r.After["Status"] != float64(i+1) {
+			t.Errorf("Expected:%s of Status %d, received:%+v", AuditInsert, i+1, records)
+		}
+	}
+
+	args = nil
+	args = args.AppendInt("ID", ids[0])
+	args = args.AppendInt("Status", 3)
+	if _, _, err = UpsertObjectContext(ctx, db, DBType, "issues", args, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	records, err = GetAuditHistoryContext(context.Background(), db, DBType, "issues", ids[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) == 0 {
+		t.Fatalf("Expected:%s, received:%+v", AuditUpdate, records)
+	}
+	if r := records[len(records)-1]; r.Operation != AuditUpdate || r.Before["Status"] != float64(1) || r.After["Status"] != float64(3) {
+		t.Errorf("Expected:%s of Status %d -> %d, received:%+v", AuditUpdate, 1, 3, records)
+	}
+	args = nil
+	args = args.AppendInt("ID", 100)
+	args = args.AppendInt("Status", 4)
+	if _, _, err = UpsertObjectContext(ctx, db, DBType, "issues", args, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	records, err = GetAuditHistoryContext(context.Background(), db, DBType, "issues", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Operation != AuditInsert || records[0].Before != nil || records[0].After["Status"] != float64(4) {
+		t.Errorf("Expected:%s of Status %d, received:%+v", AuditInsert, 4, records)
+	}
+
+	if _, err = CopyInContext(ctx, db, DBType, "issues", RowsFromSlice(rows), nil); !errors.Is(err, ErrAuditNotSupported) {
+		t.Errorf("Expected:%v, received:%v", ErrAuditNotSupported, err)
+	}
+}
@@ -16,45 +16,35 @@ import (
 	//_ "github.com/lib/pq"
 )
 
-// SQLITE, MSSQL, MYSQL, ORACLE POSTGRESQL - are database types supported.
+// SQLITE, MSSQL, MYSQL, ORACLE POSTGRESQL, COCKROACHDB, MARIADB, DUCKDB - are database types supported.
+// COCKROACHDB uses PostgreSQL driver. MARIADB uses MySQL driver, although it returns IDs of inserted rows with RETURNING clause (requires MariaDB 10.5 or later).
+// DUCKDB uses the driver registered as "duckdb", it requires cgo and is not imported by this package, so your app should import a DuckDB driver for database/sql.
 const (
 	SQLITE = iota
 	MSSQL
 	MYSQL
 	ORACLE
 	POSTGRESQL
+	COCKROACHDB
+	MARIADB
+	DUCKDB
 )
 
 // DEBUG may be set to true to print SQL queries
 const DEBUG = false
 
 // ReturnDBType gives digital representation of RDBMS type based on string.
-// Accepted db types are: "sqlite", "mssql" (or "sqlserver"), "mysql" (or "mariadb"), "oracle", "postgresql" (or "postgres").
+// Accepted db types are: "sqlite", "mssql" (or "sqlserver"), "mysql", "mariadb", "oracle", "postgresql" (or "postgres"),
+// "cockroachdb" (or "cockroach"), "duckdb", and names of other dialects registered with RegisterDialect.
 func ReturnDBType(dbtype string) byte {
-	switch dbtype {
-	case "sqlite":
-		return SQLITE
-	case "mssql":
-		return MSSQL
-	case "sqlserver":
-		return MSSQL
-	case "mysql":
-		return MYSQL
-	case "mariadb":
-		return MYSQL
-	case "oracle":
-		return ORACLE
-	case "postgres":
-		return POSTGRESQL
-	case "postgresql":
-		return POSTGRESQL
-	}
-	return 0
+	dialectsMu.RLock()
+	defer dialectsMu.RUnlock()
+	return dialectsNames[dbtype]
 }
 
 // BuildDSN creates DSN for database connection. Then, DSN is used in CreateDB and OpenSQLConnection.
-// Accepted db types are: "sqlite", "mssql" (or "sqlserver"), "mysql" (or "mariadb"), "oracle", "postgresql" (or "postgres").
-// Other arguments are self-descripting. For Oracle DBName is a service name.
+// Accepted db types are: "sqlite", "mssql" (or "sqlserver"), "mysql", "mariadb", "oracle", "postgresql" (or "postgres"), "cockroachdb" (or "cockroach"), "duckdb".
+// Other arguments are self-descripting. For Oracle DBName is a service name. For SQLite and DuckDB DBName is a file name.
 func BuildDSN(DBType string, DBName string, DBHost string, DBPort string, DBUser string, DBPassword string) (DSN string) {
 	DBTypeC := ReturnDBType(DBType)
 	if DBTypeC == SQLITE {
@@ -63,7 +53,7 @@ func BuildDSN(DBType string, DBName string, DBHost string, DBPort string, DBUser
 		//DSN = fmt.Sprintf("server=%s;user id=%s;password=%s;port=%s;database=%s;encrypt=disable;",
 		//	DBHost, DBUser, DBPassword, DBPort, DBName)
 		DSN = "sqlserver://" + url.QueryEscape(DBUser) + ":" + url.QueryEscape(DBPassword) + "@" + DBHost + ":" + DBPort + "?database=" + DBName + "&encrypt=disable"
-	} else if DBTypeC == MYSQL {
+	} else if DBTypeC == MYSQL || DBTypeC == MARIADB {
 		// This MySQL driver developers say no need for escaping
 		DSN = DBUser + ":" + DBPassword + "@tcp(" + DBHost + ":" + DBPort + ")/" + DBName
 	} else if DBTypeC == ORACLE {
@@ -71,6 +61,10 @@ func BuildDSN(DBType string, DBName string, DBHost string, DBPort string, DBUser
 	} else if DBTypeC == POSTGRESQL {
 		//DSN = "host=" + DBHost + " dbname=" + DBName + " user=" + DBUser + " password=" + DBPassword + " port=" + DBPort + " sslmode=disable"
 		DSN = "postgres://" + url.QueryEscape(DBUser) + ":" + url.QueryEscape(DBPassword) + "@" + DBHost + ":" + DBPort + "/" + DBName + "?sslmode=disable"
+	} else if DBTypeC == COCKROACHDB {
+		DSN = "postgresql://" + url.QueryEscape(DBUser) + ":" + url.QueryEscape(DBPassword) + "@" + DBHost + ":" + DBPort + "/" + DBName + "?sslmode=disable"
+	} else if DBTypeC == DUCKDB {
+		DSN = DBName
 	}
 	//log.Println(DSN)
 	return DSN
@@ -97,27 +91,16 @@ func CreateDB(DBType byte, DSN string, sqlStmt string) {
 }
 
 // OpenSQLConnection onpens connection to a database and renurns standard Go *sql.DB type.
+// The driver is defined by Dialect registered for the database type.
 // For DBType see constants, for DSN see BuildDSN.
 func OpenSQLConnection(DBType byte, DSN string) (db *sql.DB) {
 	var err error
-	var sqldriver string
-	switch DBType {
-	case SQLITE:
-		sqldriver = "sqlite3"
-	case MSSQL:
-		sqldriver = "sqlserver"
-	case MYSQL:
-		sqldriver = "mysql"
-	case ORACLE:
-		sqldriver = "oracle"
-	case POSTGRESQL:
-		sqldriver = "pgx"
-	}
+	sqldriver := GetDialect(DBType).DriverName()
 	db, err = sql.Open(sqldriver, DSN)
 	if err != nil {
 		log.Fatal("Opening SQL connection:", err)
 	}
-	if DBType == MYSQL {
+	if sqldriver == "mysql" {
 		db.SetConnMaxLifetime(time.Minute * 3) // MySQL closes connection on idle, we close first to avoid errors
 	}
 	if err = db.Ping(); err != nil {
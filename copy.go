@@ -0,0 +1,228 @@
+package sqla
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	mssql "github.com/denisenkom/go-mssqldb"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/stdlib"
+)
+
+// RowSource provides rows for CopyIn one by one, so they do not have to be loaded into memory at once.
+type RowSource interface {
+	// Next advances to the next row and reports whether there is one.
+	Next() bool
+	// Row returns the current row.
+	Row() (AnyTslice, error)
+	// Err returns an error occurred while advancing to the next row, if any.
+	Err() error
+}
+
+// sliceRows is RowSource for a slice of rows.
+type sliceRows struct {
+	rows []AnyTslice
+	i    int
+}
+
+func (s *sliceRows) Next() bool {
+	s.i++
+	return s.i <= len(s.rows)
+}
+
+func (s *sliceRows) Row() (AnyTslice, error) { return s.rows[s.i-1], nil }
+
+func (s *sliceRows) Err() error { return nil }
+
+// RowsFromSlice returns RowSource which provides rows from a slice.
+func RowsFromSlice(rows []AnyTslice) RowSource {
+	return &sliceRows{rows: rows}
+}
+
+// Copier is an optional interface of Dialect for databases having a faster way to load many rows than insert statements, see CopyIn.
+type Copier interface {
+	// CopyIn loads rows into the table columns using conn and returns the number of loaded rows.
+	// next returns the values of the next row in the order of columns, or nil if there are no more rows.
+	CopyIn(ctx context.Context, conn *sql.Conn, table string, columns []string, next func() ([]interface{}, error)) (int, error)
+}
+
+// CopyIn loads rows from src into the specified table in the fastest way available: COPY protocol for PostgreSQL and CockroachDB (pgx CopyFrom),
+// bulk copy for MSSQL (mssql.CopyIn), otherwise batched multi-row insert statements like in InsertObjects.
+// All rows should contain the same columns, the order of columns may differ. Rows are loaded in one transaction, so either all rows are loaded or none.
+// progress is called, if not nil, with the number of rows passed to the database so far after every InsertRowsBatch rows and after the last row.
+//
+// For PostgreSQL the table and column names are converted to lower case, the same way as the database does for unquoted names.
+// CopyIn does not write audit records, for tables with audit enabled (see TableMeta.Audit) it returns an error wrapping ErrAuditNotSupported, use InsertObjects for them.
+// It returns the number of loaded rows. Any error is only logged, use CopyInContext to receive it.
+func CopyIn(db *sql.DB, DBType byte, table string, src RowSource, progress func(rows int)) (rowsaff int) {
+	rowsaff, err := CopyInContext(context.Background(), db, DBType, table, src, progress)
+	if err != nil {
+		log.Println(err)
+	}
+	return rowsaff
+}
+
+// CopyInContext does the same as CopyIn, but it takes a context and returns an error instead of logging it.
+func CopyInContext(ctx context.Context, db *sql.DB, DBType byte, table string, src RowSource, progress func(rows int)) (rowsaff int, err error) {
+	meta := GetTableMeta(table)
+	if meta.Audit {
+		return 0, wrapError(currentFunction(), ErrAuditNotSupported)
+	}
+	if !src.Next() {
+		if err = src.Err(); err != nil {
+			return 0, fmt.Errorf("%s: %w", currentFunction(), err)
+		}
+		return 0, nil
+	}
+	first, err := src.Row()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", currentFunction(), err)
+	}
+	columns, index, err := rowColumns(meta, first)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", currentFunction(), err)
+	}
+
+	var passed int
+	row := first
+	next := func() ([]interface{}, error) {
+		if row == nil {
+			if !src.Next() {
+				if progress != nil && passed%InsertRowsBatch != 0 {
+					progress(passed)
+				}
+				return nil, src.Err()
+			}
+			var err error
+			if row, err = src.Row(); err != nil {
+				return nil, err
+			}
+		}
+		args, err := rowArgs(index, row)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", passed, err)
+		}
+		row = nil
+		passed++
+		if progress != nil && passed%InsertRowsBatch == 0 {
+			progress(passed)
+		}
+		return args, nil
+	}
+
+	if copier, ok := GetDialect(DBType).(Copier); ok {
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			return 0, wrapError(currentFunction(), err)
+		}
+		defer conn.Close()
+		rowsaff, err = copier.CopyIn(ctx, conn, table, columns, next)
+		if err != nil {
+			return 0, wrapError(currentFunction(), err)
+		}
+		return rowsaff, nil
+	}
+
+	batch := insertBatchSize(DBType, len(columns))
+	err = runTx(ctx, db, nil, func(tx *sql.Tx) error {
+		var values [][]interface{}
+		for {
+			args, err := next()
+			if err != nil {
+				return err
+			}
+			if args != nil {
+				values = append(values, args)
+			}
+			if len(values) == batch || (args == nil && len(values) > 0) {
+				_, ra, err := insertRows(ctx, tx, DBType, table, meta.quoteAll(DBType, columns), values, "")
+				if err != nil {
+					return err
+				}
+				rowsaff += ra
+				values = values[:0]
+			}
+			if args == nil {
+				return nil
+			}
+		}
+	})
+	if err != nil {
+		return 0, wrapError(currentFunction(), err)
+	}
+	return rowsaff, nil
+}
+
+// copyFromSource adapts next function of Copier to pgx.CopyFromSource.
+type copyFromSource struct {
+	next   func() ([]interface{}, error)
+	values []interface{}
+	err    error
+}
+
+func (s *copyFromSource) Next() bool {
+	s.values, s.err = s.next()
+	return s.values != nil
+}
+
+func (s *copyFromSource) Values() ([]interface{}, error) { return s.values, nil }
+
+func (s *copyFromSource) Err() error { return s.err }
+
+// CopyIn implements Copier using COPY protocol of pgx driver.
+func (PostgreSQLDialect) CopyIn(ctx context.Context, conn *sql.Conn, table string, columns []string, next func() ([]interface{}, error)) (int, error) {
+	var copied int64
+	err := conn.Raw(func(driverConn interface{}) error {
+		pgxConn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return errors.New("connection is not made by pgx driver")
+		}
+		lowerColumns := make([]string, len(columns))
+		for i := range columns {
+			lowerColumns[i] = strings.ToLower(columns[i])
+		}
+		var err error
+		copied, err = pgxConn.Conn().CopyFrom(ctx, pgx.Identifier(strings.Split(strings.ToLower(table), ".")), lowerColumns, &copyFromSource{next: next})
+		return err
+	})
+	return int(copied), err
+}
+
+// CopyIn implements Copier using bulk copy of go-mssqldb driver. Rows are copied in a transaction.
+func (MSSQLDialect) CopyIn(ctx context.Context, conn *sql.Conn, table string, columns []string, next func() ([]interface{}, error)) (int, error) {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	stmt, err := tx.PrepareContext(ctx, mssql.CopyIn(table, mssql.BulkOptions{}, columns...))
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+	for {
+		args, err := next()
+		if err != nil {
+			return 0, err
+		}
+		if args == nil {
+			break
+		}
+		if _, err = stmt.ExecContext(ctx, args...); err != nil {
+			return 0, err
+		}
+	}
+	res, err := stmt.ExecContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	copied, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(copied), tx.Commit()
+}
@@ -0,0 +1,49 @@
+package sqla
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+)
+
+func TestCopyIn(t *testing.T) {
+	var DBType byte
+	DBType = SQLITE
+	var db *sql.DB
+	db = OpenSQLConnection(DBType, "file:copytest?mode=memory&cache=shared")
+	defer db.Close()
+	db.Exec("CREATE TABLE measures (ID INTEGER PRIMARY KEY, Sensor TEXT, Value REAL);")
+
+	batch := InsertRowsBatch
+	InsertRowsBatch = 2
+	defer func() { InsertRowsBatch = batch }()
+
+	var rows []AnyTslice
+	for i := 0; i < 5; i++ {
+		var args AnyTslice
+		args = args.AppendNonEmptyString("Sensor", "t1")
+		args = args.AppendFloat64("Value", float64(i)/2)
+		rows = append(rows, args)
+	}
+	var reported []int
+	rowsaff := CopyIn(db, DBType, "measures", RowsFromSlice(rows), func(n int) { reported = append(reported, n) })
+	if rowsaff != 5 {
+		t.Errorf("Expected:%d, received:%d", 5, rowsaff)
+	}
+	if !intSlicesEqual(reported, []int{2, 4, 5}) {
+		t.Errorf("Expected:%v, received:%v", []int{2, 4, 5}, reported)
+	}
+
+	rows[3] = rows[3][:1]
+	if rowsaff = CopyIn(db, DBType, "measures", RowsFromSlice(rows), nil); rowsaff != 0 {
+		t.Errorf("Expected:%d, received:%d", 0, rowsaff)
+	}
+	if _, err := CopyInContext(context.Background(), db, DBType, "measures", RowsFromSlice([]AnyTslice{{}}), nil); err == nil {
+		t.Errorf("Expected an error on rows without columns, received nil")
+	}
+	var count int
+	db.QueryRow("SELECT COUNT(*) FROM measures").Scan(&count)
+	if count != 5 {
+		t.Errorf("Expected:%d, received:%d", 5, count)
+	}
+}
@@ -1,100 +1,215 @@
 package sqla
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
 	"log"
-	"sort"
 )
 
 // VerifyRemovalPermissions makes query to check if a user has the right to delete an object.
 // The returned result will be truth if either an Owner matches id in column 'Creator' or have AdminPrivileges is true.
+// ids are values of key column ('ID' unless other is registered with RegisterTable).
 // RemoveAllowed flag defines if any remove allowed at all by non-admin user.
-// This function is somewhat specific to EDM project. You might need to modify it for your app.
-func VerifyRemovalPermissions(db *sql.DB, DBType byte, table string, Owner int, AdminPrivileges bool, RemoveAllowed bool, ids []int) bool {
+// This function is somewhat specific to EDM project, see VerifyPermissions and Policy for configurable checks.
+func VerifyRemovalPermissions(db Executor, DBType byte, table string, Owner int, AdminPrivileges bool, RemoveAllowed bool, ids []int) bool {
 	if AdminPrivileges {
 		return true
 	}
 	if !RemoveAllowed {
 		return false
 	}
-	var argsCounter int
-	var args, argstoAppend []interface{}
-	var sqlids []int
-
-	argsCounter++
-	var sq = "SELECT ID FROM " + table + " WHERE Creator = " + MakeParam(DBType, argsCounter) + " "
-	args = append(args, Owner)
-
-	argsCounter, sq, argstoAppend = BuildSQLIN(DBType, sq, argsCounter, "ID", ids)
-	args = append(args, argstoAppend...)
-
-	if DEBUG {
-		log.Println(sq, args)
+	if err := ValidateColumns(table); err != nil {
+		log.Println(fmt.Errorf("%s: %w", currentFunction(), err))
+		return false
 	}
-	rows, err := db.Query(sq, args...)
+	meta := GetTableMeta(table)
+	meta.Policy = &Policy{OwnerColumns: []string{"Creator"}}
+	permitted, err := verifyPermissions(context.Background(), db, DBType, currentFunction(), meta, Subject{UserID: Owner}, ActionDelete, ids)
 	if err != nil {
-		log.Println(currentFunction()+":", err)
+		log.Println(err)
 	}
-	defer rows.Close()
-	var ID sql.NullInt64
-	for rows.Next() {
-		err = rows.Scan(&ID)
-		if err != nil {
-			log.Println(currentFunction()+":", err)
-		}
-		sqlids = append(sqlids, int(ID.Int64))
+	return permitted
+}
+
+// DeleteObject just deletes one specific object which id is in column specified.
+// If soft delete is enabled for the table (see TableMeta.SoftDeleteColumn), rows are marked as deleted instead of removing them.
+// Any error is only logged, use DeleteObjectContext to receive it.
+func DeleteObject(db Executor, DBType byte, table string, column string, id int) (rowsaff int) {
+	rowsaff, err := DeleteObjectContext(context.Background(), db, DBType, table, column, id)
+	if err != nil {
+		log.Println(err)
 	}
-	sort.Ints(ids)
-	sort.Ints(sqlids)
-	if intSlicesEqual(ids, sqlids) {
-		return true
+	return rowsaff
+}
+
+// DeleteObjectContext does the same as DeleteObject, but it takes a context and returns an error instead of logging it.
+func DeleteObjectContext(ctx context.Context, db Executor, DBType byte, table string, column string, id int) (rowsaff int, err error) {
+	if err = ValidateColumns(table, column); err != nil {
+		return 0, fmt.Errorf("%s: %w", currentFunction(), err)
 	}
+	meta := GetTableMeta(table)
+	if ok, err := auditInTx(ctx, db, meta, func(tx *sql.Tx) (err error) {
+		rowsaff, err = DeleteObjectContext(ctx, tx, DBType, table, column, id)
+		return err
+	}); ok {
+		return rowsaff, err
+	}
+	data := &HookData{Table: table, Column: column, Keys: []interface{}{id}}
+	return deleteWithHooks(ctx, currentFunction(), data, func() (int, error) {
+		return meta.deleteWhere(ctx, db, DBType, currentFunction(), func(sq string, argsCounter int) (int, string, []interface{}, error) {
+			argsCounter, sq, args := buildSQLCOMPARE(DBType, sq, argsCounter, column, " = ", id)
+			return argsCounter, sq, args, nil
+		})
+	})
+}
 
-	return false
+// DeleteObjectByKey deletes one specific object defined by key values, one value for each key column registered with RegisterTable.
+// It should be used for tables with composite or non-integer keys. Any error is only logged, use DeleteObjectByKeyContext to receive it.
+// If soft delete is enabled for the table (see TableMeta.SoftDeleteColumn), rows are marked as deleted instead of removing them.
+func DeleteObjectByKey(db Executor, DBType byte, table string, key ...interface{}) (rowsaff int) {
+	rowsaff, err := DeleteObjectByKeyContext(context.Background(), db, DBType, table, key...)
+	if err != nil {
+		log.Println(err)
+	}
+	return rowsaff
 }
 
-// DeleteObject just deletes one specific object which id is in column specified.
-func DeleteObject(db *sql.DB, DBType byte, table string, column string, id int) (rowsaff int) {
-	var sq = "DELETE FROM " + table + " WHERE " + column + " = " + MakeParam(DBType, 1)
-	if DEBUG {
-		log.Println(sq, id)
+// DeleteObjectByKeyContext does the same as DeleteObjectByKey, but it takes a context and returns an error instead of logging it.
+func DeleteObjectByKeyContext(ctx context.Context, db Executor, DBType byte, table string, key ...interface{}) (rowsaff int, err error) {
+	if err = ValidateColumns(table); err != nil {
+		return 0, fmt.Errorf("%s: %w", currentFunction(), err)
 	}
-	res, err := db.Exec(sq, id)
-	if err != nil {
-		log.Println(currentFunction()+":", err)
+	meta := GetTableMeta(table)
+	if ok, err := auditInTx(ctx, db, meta, func(tx *sql.Tx) (err error) {
+		rowsaff, err = DeleteObjectByKeyContext(ctx, tx, DBType, table, key...)
+		return err
+	}); ok {
+		return rowsaff, err
 	}
-	ra, err := res.RowsAffected()
+	data := &HookData{Table: table, Keys: key}
+	return deleteWithHooks(ctx, currentFunction(), data, func() (int, error) {
+		return meta.deleteWhere(ctx, db, DBType, currentFunction(), func(sq string, argsCounter int) (int, string, []interface{}, error) {
+			return meta.buildKeyWhere(DBType, sq, argsCounter, key)
+		})
+	})
+}
+
+// DeleteObjects just deletes any object which id is present in ids list and in column specified.
+// If ids list exceeds the number of parameters allowed by the database, several statements are executed, so use a transaction (see WithTx) if they should be atomic.
+// If soft delete is enabled for the table (see TableMeta.SoftDeleteColumn), rows are marked as deleted instead of removing them.
+// Any error is only logged, use DeleteObjectsContext to receive it.
+func DeleteObjects(db Executor, DBType byte, table string, column string, ids []int) (rowsaff int) {
+	rowsaff, err := DeleteObjectsContext(context.Background(), db, DBType, table, column, ids)
 	if err != nil {
-		log.Println(currentFunction()+":", err)
+		log.Println(err)
 	}
-	rowsaff = int(ra)
 	return rowsaff
 }
 
-// DeleteObjects just deletes any object which id is present in ids list and in column specified.
-func DeleteObjects(db *sql.DB, DBType byte, table string, column string, ids []int) (rowsaff int) {
+// DeleteObjectsContext does the same as DeleteObjects, but it takes a context and returns an error instead of logging it.
+func DeleteObjectsContext(ctx context.Context, db Executor, DBType byte, table string, column string, ids []int) (rowsaff int, err error) {
+	return deleteInChunks(ctx, db, DBType, currentFunction(), table, column, ids)
+}
 
-	var sq = "DELETE FROM " + table + " "
-	var args, argstoAppend []interface{}
-	var argsCounter int
-	var res sql.Result
-	var err error
+// DeleteObjectsByKeys deletes any object which key is present in keys list. The key column is 'ID' unless other is registered with RegisterTable,
+// keys may be of any type, e.g. strings or UUIDs. Any error is only logged, use DeleteObjectsByKeysContext to receive it.
+// If soft delete is enabled for the table (see TableMeta.SoftDeleteColumn), rows are marked as deleted instead of removing them.
+func DeleteObjectsByKeys[K comparable](db Executor, DBType byte, table string, keys []K) (rowsaff int) {
+	rowsaff, err := DeleteObjectsByKeysContext(context.Background(), db, DBType, table, keys)
+	if err != nil {
+		log.Println(err)
+	}
+	return rowsaff
+}
 
-	if len(ids) > 0 {
-		argsCounter, sq, argstoAppend = BuildSQLIN(DBType, sq, argsCounter, column, ids)
-		args = append(args, argstoAppend...)
-		if DEBUG {
-			log.Println(sq, args)
-		}
-		res, err = db.Exec(sq, args...)
-		if err != nil {
-			log.Println(currentFunction()+":", err)
+// DeleteObjectsByKeysContext does the same as DeleteObjectsByKeys, but it takes a context and returns an error instead of logging it.
+func DeleteObjectsByKeysContext[K comparable](ctx context.Context, db Executor, DBType byte, table string, keys []K) (rowsaff int, err error) {
+	keyColumn, err := GetTableMeta(table).keyColumn()
+	if err != nil {
+		return 0, wrapError(currentFunction(), err)
+	}
+	return deleteInChunks(ctx, db, DBType, currentFunction(), table, keyColumn, keys)
+}
+
+// deleteInChunks deletes objects which values are present in the list and in column specified.
+// If the list exceeds the number of parameters allowed by the database, several statements are executed.
+func deleteInChunks[T comparable](ctx context.Context, db Executor, DBType byte, fname string, table string, column string, valueList []T) (rowsaff int, err error) {
+	if err = ValidateColumns(table, column); err != nil {
+		return 0, fmt.Errorf("%s: %w", fname, err)
+	}
+	if len(valueList) == 0 {
+		return rowsaff, nil
+	}
+	meta := GetTableMeta(table)
+	if ok, err := auditInTx(ctx, db, meta, func(tx *sql.Tx) (err error) {
+		rowsaff, err = deleteInChunks(ctx, tx, DBType, fname, table, column, valueList)
+		return err
+	}); ok {
+		return rowsaff, err
+	}
+	data := &HookData{Table: table, Column: column}
+	for _, v := range valueList {
+		data.Keys = append(data.Keys, v)
+	}
+	return deleteWithHooks(ctx, fname, data, func() (rowsaff int, err error) {
+		reserved, _, _ := meta.deleteStatement(DBType)
+		for _, chunk := range chunkValues(DBType, valueList, reserved+1) {
+			ra, err := meta.deleteWhere(ctx, db, DBType, fname, func(sq string, argsCounter int) (int, string, []interface{}, error) {
+				argsCounter, sq, args := BuildSQLINOf(DBType, sq, argsCounter, column, chunk)
+				return argsCounter, sq, args, nil
+			})
+			rowsaff += ra
+			if err != nil {
+				return rowsaff, err
+			}
 		}
-		ra, err := res.RowsAffected()
+		return rowsaff, nil
+	})
+}
+
+// deleteWithHooks calls BeforeDelete hooks, then del function to delete objects, then AfterDelete hooks.
+func deleteWithHooks(ctx context.Context, fname string, data *HookData, del func() (int, error)) (rowsaff int, err error) {
+	if err = runHooks(ctx, beforeDelete, data); err != nil {
+		return 0, fmt.Errorf("%s: %w", fname, err)
+	}
+	if rowsaff, err = del(); err != nil {
+		return rowsaff, err
+	}
+	data.RowsAffected = rowsaff
+	if err = runHooks(ctx, afterDelete, data); err != nil {
+		return rowsaff, fmt.Errorf("%s: %w", fname, err)
+	}
+	return rowsaff, nil
+}
+
+// deleteWhere deletes (or marks as deleted) rows of the table matching the condition added by where to a statement, and writes audit records if audit is enabled.
+func (m TableMeta) deleteWhere(ctx context.Context, db Executor, DBType byte, fname string, where func(sq string, argsCounter int) (int, string, []interface{}, error)) (rowsaff int, err error) {
+	argsCounter, sq, args := m.deleteStatement(DBType)
+	return m.execAudited(ctx, db, DBType, fname, AuditDelete, argsCounter, sq, args, nil, func(sq string, argsCounter int) (int, string, []interface{}, error) {
+		argsCounter, sq, args, err := where(sq, argsCounter)
 		if err != nil {
-			log.Println(currentFunction()+":", err)
+			return 0, "", nil, err
 		}
-		rowsaff = int(ra)
+		argsCounter, sq, args = m.buildDeleteCondition(DBType, sq, argsCounter, args)
+		return argsCounter, sq, args, nil
+	})
+}
+
+// deleteStatement returns 'DELETE FROM table ' statement, or 'UPDATE table SET column = $1 ' statement if soft delete is enabled for the table.
+func (m TableMeta) deleteStatement(DBType byte) (argsCounter int, sq string, args []interface{}) {
+	if m.SoftDeleteColumn == "" {
+		return 0, "DELETE FROM " + m.Name + " ", nil
 	}
-	return rowsaff
+	sq, args = m.softDeleteSet(DBType)
+	return len(args), sq, args
+}
+
+// buildDeleteCondition adds to the statement made by deleteStatement the condition to skip rows which are already soft-deleted, if soft delete is enabled.
+func (m TableMeta) buildDeleteCondition(DBType byte, sq string, argsCounter int, args []interface{}) (counter int, resquery string, resargs []interface{}) {
+	if m.SoftDeleteColumn == "" {
+		return argsCounter, sq, args
+	}
+	argsCounter, sq, argstoAppend := m.buildNotDeleted(DBType, sq, argsCounter)
+	return argsCounter, sq, append(args, argstoAppend...)
 }
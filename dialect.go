@@ -0,0 +1,593 @@
+package sqla
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// IDStrategy defines how a database returns the ID of an inserted row, see Dialect.InsertReturningID.
+type IDStrategy byte
+
+// LastInsertID - ID is taken from sql.Result.LastInsertId;
+// QueryRowID - insert statement returns a row with ID (e.g. RETURNING or OUTPUT clause);
+// OutParamID - ID is returned into an out parameter which is the last placeholder of insert statement (e.g. RETURNING INTO clause);
+// SelectID - ID is selected by a separate statement using the values of conflict columns (see Dialect.Upsert).
+const (
+	LastInsertID IDStrategy = iota
+	QueryRowID
+	OutParamID
+	SelectID
+)
+
+// Dialect describes the specifics of SQL syntax and of database/sql driver for a particular RDBMS.
+// Dialects for all supported databases are registered by default, see RegisterDialect to add or replace one.
+// Dialect implementations of this package are exported, so you can embed one of them in your type and override only some methods.
+type Dialect interface {
+	// DriverName returns the name of database/sql driver to open connection with.
+	DriverName() string
+	// Placeholder returns placeholder for a parameter with positional number argsCounter, e.g. $1 or ?.
+	Placeholder(argsCounter int) string
+	// NumberedPlaceholders reports whether one numbered placeholder may be referenced several times in a statement.
+	NumberedPlaceholders() bool
+	// Pagination returns a clause to add at the end of select statement to limit and offset rows, and arguments for it.
+	// Parameters in the clause should be marked with ?, they are replaced with placeholders when the clause is added to a statement.
+	Pagination(limit int, offset int) (clause string, args []interface{})
+	// TextSearch returns the operator to search text val (e.g. " LIKE ") and reports whether case-insensitive search should be added explicitly.
+	TextSearch(val string) (operator string, caseInsensitive bool)
+	// LikeCollation returns collation to add after LIKE operand for case-insensitive search (if any), e.g. "COLLATE binary_ai ".
+	// If it is empty, case-insensitive search is made by comparing with the value in several cases.
+	LikeCollation() string
+	// OrderCollation returns collation to add after each column in ORDER BY (if any), e.g. " COLLATE NOCASE".
+	OrderCollation() string
+	// InsertReturningID returns insert statement which returns the ID of inserted row and the way to get it.
+	// columns and values are comma-separated lists of columns and placeholders, outParam is the placeholder to use for OutParamID strategy.
+	InsertReturningID(table string, columns string, values string, idColumn string, outParam string) (sq string, strategy IDStrategy)
+	// InsertRows returns insert statement for several rows, values contains comma-separated placeholders for each row.
+	// If idColumn is not empty and the database can return IDs of inserted rows, the statement returns them as rows and returnsIDs is true.
+	InsertRows(table string, columns string, values []string, idColumn string) (sq string, returnsIDs bool)
+	// Upsert returns statement which inserts a row, or updates updateColumns of the existing row if it has the same values in conflictColumns,
+	// and the way to get the ID of the row. values contains a placeholder for each of columns. If idColumn is empty the ID is not returned.
+	Upsert(table string, columns []string, values []string, conflictColumns []string, updateColumns []string, idColumn string) (sq string, strategy IDStrategy)
+	// QuoteIdentifier quotes a table or column name, e.g. "name" or [name]. Each part of dotted name is quoted separately.
+	QuoteIdentifier(name string) string
+	// MaxParams returns the maximum number of parameters in one statement, or 0 if there is no limit.
+	// Functions which take lists of values execute several statements if the limit is exceeded.
+	MaxParams() int
+	// MaxInList returns the maximum number of values in one IN list, or 0 if there is no limit.
+	// Longer lists are split into several IN lists joined with OR.
+	MaxInList() int
+	// CaseInsensitiveEquality reports whether = and IN compare strings case-insensitively with default collations.
+	// If not, case-insensitive comparison is made with UPPER function.
+	CaseInsensitiveEquality() bool
+	// BoolValue returns the argument to pass for a boolean value, e.g. true or 1 for databases without boolean type.
+	BoolValue(b bool) interface{}
+}
+
+var (
+	dialectsMu    sync.RWMutex
+	dialects      = map[byte]Dialect{}
+	dialectsNames = map[string]byte{}
+)
+
+func init() {
+	RegisterDialect(SQLITE, SQLiteDialect{}, "sqlite")
+	RegisterDialect(MSSQL, MSSQLDialect{}, "mssql", "sqlserver")
+	RegisterDialect(MYSQL, MySQLDialect{}, "mysql")
+	RegisterDialect(ORACLE, OracleDialect{}, "oracle")
+	RegisterDialect(POSTGRESQL, PostgreSQLDialect{}, "postgresql", "postgres")
+	RegisterDialect(COCKROACHDB, CockroachDBDialect{}, "cockroachdb", "cockroach")
+	RegisterDialect(MARIADB, MariaDBDialect{}, "mariadb")
+	RegisterDialect(DUCKDB, DuckDBDialect{}, "duckdb")
+}
+
+// RegisterDialect registers Dialect d for the database type DBType, replacing the one registered before if any.
+// names are accepted by ReturnDBType and BuildDSN for this database type.
+// A new database type should not be equal to the constants of this package.
+func RegisterDialect(DBType byte, d Dialect, names ...string) {
+	dialectsMu.Lock()
+	defer dialectsMu.Unlock()
+	dialects[DBType] = d
+	for _, name := range names {
+		dialectsNames[name] = DBType
+	}
+}
+
+// GetDialect returns Dialect registered for the database type. For unknown types it returns a dialect with ? placeholders and LIMIT/OFFSET pagination.
+func GetDialect(DBType byte) Dialect {
+	dialectsMu.RLock()
+	d, ok := dialects[DBType]
+	dialectsMu.RUnlock()
+	if !ok {
+		return genericDialect{}
+	}
+	return d
+}
+
+// quoteIdentifier quotes every part of dotted name with opening and closing quotes, doubling closing quotes inside a name.
+func quoteIdentifier(name string, open string, close string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		if part == "*" {
+			continue
+		}
+		parts[i] = open + strings.Replace(part, close, close+close, -1) + close
+	}
+	return strings.Join(parts, ".")
+}
+
+// BoolValue returns the argument for a boolean value in the database, see Dialect.BoolValue.
+func BoolValue(DBType byte, b bool) interface{} {
+	return GetDialect(DBType).BoolValue(b)
+}
+
+// boolInt returns 1 for true and 0 for false.
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// bindMarkers replaces every ? in a part of statement with a placeholder for the next parameter.
+func bindMarkers(DBType byte, argsCounter int, part string) (counter int, resquery string) {
+	for _, r := range part {
+		if r == '?' {
+			argsCounter++
+			resquery += MakeParam(DBType, argsCounter)
+		} else {
+			resquery += string(r)
+		}
+	}
+	return argsCounter, resquery
+}
+
+// insertRowsReturning makes multi-row insert statement with RETURNING clause if idColumn is not empty.
+func insertRowsReturning(table string, columns string, values []string, idColumn string) (string, bool) {
+	sq := "INSERT INTO " + table + " (" + columns + ") VALUES (" + strings.Join(values, "), (") + ")"
+	if idColumn == "" {
+		return sq, false
+	}
+	return sq + " RETURNING " + idColumn, true
+}
+
+// upsertOnConflict makes insert statement with ON CONFLICT DO UPDATE clause, and RETURNING clause if idColumn is not empty.
+func upsertOnConflict(table string, columns []string, values []string, conflictColumns []string, updateColumns []string, idColumn string) (string, IDStrategy) {
+	var set []string
+	for _, col := range updateColumns {
+		set = append(set, col+" = EXCLUDED."+col)
+	}
+	sq := "INSERT INTO " + table + " (" + strings.Join(columns, ", ") + ") VALUES (" + strings.Join(values, ", ") + ") ON CONFLICT (" +
+		strings.Join(conflictColumns, ", ") + ") DO UPDATE SET " + strings.Join(set, ", ")
+	if idColumn == "" {
+		return sq, LastInsertID
+	}
+	return sq + " RETURNING " + idColumn, QueryRowID
+}
+
+// mergeCondition makes 'target.column = source.column AND ...' condition for MERGE statement.
+func mergeCondition(columns []string) string {
+	var cond []string
+	for _, col := range columns {
+		cond = append(cond, "target."+col+" = source."+col)
+	}
+	return strings.Join(cond, " AND ")
+}
+
+// mergeSet makes 'target.column = source.column, ...' list for UPDATE SET part of MERGE statement.
+func mergeSet(columns []string) string {
+	var set []string
+	for _, col := range columns {
+		set = append(set, "target."+col+" = source."+col)
+	}
+	return strings.Join(set, ", ")
+}
+
+// mergeValues makes 'source.column, ...' list for INSERT part of MERGE statement.
+func mergeValues(columns []string) string {
+	var vals []string
+	for _, col := range columns {
+		vals = append(vals, "source."+col)
+	}
+	return strings.Join(vals, ", ")
+}
+
+// SQLiteDialect is Dialect for SQLite.
+type SQLiteDialect struct{}
+
+// DriverName implements Dialect.
+func (SQLiteDialect) DriverName() string { return "sqlite3" }
+
+// Placeholder implements Dialect.
+func (SQLiteDialect) Placeholder(argsCounter int) string { return "$" + strconv.Itoa(argsCounter) }
+
+// NumberedPlaceholders implements Dialect.
+func (SQLiteDialect) NumberedPlaceholders() bool { return true }
+
+// Pagination implements Dialect.
+func (SQLiteDialect) Pagination(limit int, offset int) (string, []interface{}) {
+	return " LIMIT ? OFFSET ?", []interface{}{limit, offset}
+}
+
+// TextSearch implements Dialect. SQLite LIKE is case-insensitive only for ASCII characters.
+func (SQLiteDialect) TextSearch(val string) (string, bool) { return " LIKE ", !isStringASCII(val) }
+
+// LikeCollation implements Dialect.
+func (SQLiteDialect) LikeCollation() string { return "" }
+
+// OrderCollation implements Dialect.
+func (SQLiteDialect) OrderCollation() string { return " COLLATE NOCASE" }
+
+// InsertReturningID implements Dialect.
+func (SQLiteDialect) InsertReturningID(table string, columns string, values string, idColumn string, outParam string) (string, IDStrategy) {
+	return "INSERT INTO " + table + " (" + columns + ") VALUES (" + values + ")", LastInsertID
+}
+
+// InsertRows implements Dialect. RETURNING clause is supported since SQLite 3.35.
+func (SQLiteDialect) InsertRows(table string, columns string, values []string, idColumn string) (string, bool) {
+	return insertRowsReturning(table, columns, values, idColumn)
+}
+
+// Upsert implements Dialect. ON CONFLICT clause is supported since SQLite 3.24, conflictColumns should have a unique index.
+func (SQLiteDialect) Upsert(table string, columns []string, values []string, conflictColumns []string, updateColumns []string, idColumn string) (string, IDStrategy) {
+	return upsertOnConflict(table, columns, values, conflictColumns, updateColumns, idColumn)
+}
+
+// QuoteIdentifier implements Dialect.
+func (SQLiteDialect) QuoteIdentifier(name string) string { return quoteIdentifier(name, `"`, `"`) }
+
+// MaxParams implements Dialect.
+func (SQLiteDialect) MaxParams() int { return 32766 }
+
+// MaxInList implements Dialect.
+func (SQLiteDialect) MaxInList() int { return 0 }
+
+// CaseInsensitiveEquality implements Dialect.
+func (SQLiteDialect) CaseInsensitiveEquality() bool { return false }
+
+// BoolValue implements Dialect. There is no boolean type, 1 or 0 is used.
+func (SQLiteDialect) BoolValue(b bool) interface{} { return boolInt(b) }
+
+// MSSQLDialect is Dialect for Microsoft SQL Server.
+type MSSQLDialect struct{}
+
+// DriverName implements Dialect.
+func (MSSQLDialect) DriverName() string { return "sqlserver" }
+
+// Placeholder implements Dialect.
+func (MSSQLDialect) Placeholder(argsCounter int) string { return "@p" + strconv.Itoa(argsCounter) }
+
+// NumberedPlaceholders implements Dialect.
+func (MSSQLDialect) NumberedPlaceholders() bool { return true }
+
+// Pagination implements Dialect.
+func (MSSQLDialect) Pagination(limit int, offset int) (string, []interface{}) {
+	return " OFFSET ? ROWS FETCH NEXT ? ROWS ONLY", []interface{}{offset, limit}
+}
+
+// TextSearch implements Dialect.
+func (MSSQLDialect) TextSearch(val string) (string, bool) { return " LIKE ", false }
+
+// LikeCollation implements Dialect.
+func (MSSQLDialect) LikeCollation() string { return "" }
+
+// OrderCollation implements Dialect.
+func (MSSQLDialect) OrderCollation() string { return "" }
+
+// InsertReturningID implements Dialect. The ID is output into a table variable, so the statement works with tables having triggers.
+// The variable column is sql_variant to hold integer and uniqueidentifier keys.
+func (MSSQLDialect) InsertReturningID(table string, columns string, values string, idColumn string, outParam string) (string, IDStrategy) {
+	return `DECLARE @virttable TABLE (NewID sql_variant);
+		INSERT INTO ` + table + ` (` + columns + `) OUTPUT INSERTED.` + idColumn + ` INTO @virttable VALUES (` + values + `);
+		SELECT NewID FROM @virttable`, QueryRowID
+}
+
+// InsertRows implements Dialect. IDs are output into a table variable the same way as in InsertReturningID.
+func (MSSQLDialect) InsertRows(table string, columns string, values []string, idColumn string) (string, bool) {
+	if idColumn == "" {
+		return "INSERT INTO " + table + " (" + columns + ") VALUES (" + strings.Join(values, "), (") + ")", false
+	}
+	return `DECLARE @virttable TABLE (NewID sql_variant);
+		INSERT INTO ` + table + ` (` + columns + `) OUTPUT INSERTED.` + idColumn + ` INTO @virttable VALUES (` + strings.Join(values, "), (") + `);
+		SELECT NewID FROM @virttable`, true
+}
+
+// Upsert implements Dialect. The statement is MERGE with HOLDLOCK hint to avoid race conditions, the ID is output the same way as in InsertReturningID.
+func (MSSQLDialect) Upsert(table string, columns []string, values []string, conflictColumns []string, updateColumns []string, idColumn string) (string, IDStrategy) {
+	sq := "MERGE INTO " + table + " WITH (HOLDLOCK) AS target USING (VALUES (" + strings.Join(values, ", ") + ")) AS source (" + strings.Join(columns, ", ") + ") ON " +
+		mergeCondition(conflictColumns) + " WHEN MATCHED THEN UPDATE SET " + mergeSet(updateColumns) +
+		" WHEN NOT MATCHED THEN INSERT (" + strings.Join(columns, ", ") + ") VALUES (" + mergeValues(columns) + ")"
+	if idColumn == "" {
+		return sq + ";", LastInsertID
+	}
+	return `DECLARE @virttable TABLE (NewID sql_variant);
+		` + sq + ` OUTPUT INSERTED.` + idColumn + ` INTO @virttable;
+		SELECT NewID FROM @virttable`, QueryRowID
+}
+
+// QuoteIdentifier implements Dialect.
+func (MSSQLDialect) QuoteIdentifier(name string) string { return quoteIdentifier(name, "[", "]") }
+
+// MaxParams implements Dialect.
+func (MSSQLDialect) MaxParams() int { return 2100 }
+
+// MaxInList implements Dialect.
+func (MSSQLDialect) MaxInList() int { return 0 }
+
+// CaseInsensitiveEquality implements Dialect. Default collations are case-insensitive.
+func (MSSQLDialect) CaseInsensitiveEquality() bool { return true }
+
+// BoolValue implements Dialect. There is no boolean type, 1 or 0 is used.
+func (MSSQLDialect) BoolValue(b bool) interface{} { return boolInt(b) }
+
+// MySQLDialect is Dialect for MySQL.
+type MySQLDialect struct{}
+
+// DriverName implements Dialect.
+func (MySQLDialect) DriverName() string { return "mysql" }
+
+// Placeholder implements Dialect.
+func (MySQLDialect) Placeholder(argsCounter int) string { return "?" }
+
+// NumberedPlaceholders implements Dialect.
+func (MySQLDialect) NumberedPlaceholders() bool { return false }
+
+// Pagination implements Dialect.
+func (MySQLDialect) Pagination(limit int, offset int) (string, []interface{}) {
+	return " LIMIT ? OFFSET ?", []interface{}{limit, offset}
+}
+
+// TextSearch implements Dialect. Default MySQL collations are case-insensitive.
+func (MySQLDialect) TextSearch(val string) (string, bool) { return " LIKE ", false }
+
+// LikeCollation implements Dialect.
+func (MySQLDialect) LikeCollation() string { return "" }
+
+// OrderCollation implements Dialect.
+func (MySQLDialect) OrderCollation() string { return "" }
+
+// InsertReturningID implements Dialect.
+func (MySQLDialect) InsertReturningID(table string, columns string, values string, idColumn string, outParam string) (string, IDStrategy) {
+	return "INSERT INTO " + table + " (" + columns + ") VALUES (" + values + ")", LastInsertID
+}
+
+// InsertRows implements Dialect. IDs of inserted rows are not returned.
+func (MySQLDialect) InsertRows(table string, columns string, values []string, idColumn string) (string, bool) {
+	return "INSERT INTO " + table + " (" + columns + ") VALUES (" + strings.Join(values, "), (") + ")", false
+}
+
+// Upsert implements Dialect. ON DUPLICATE KEY UPDATE clause does not take conflictColumns, any unique key of the table is checked.
+// The ID of updated row is returned by LAST_INSERT_ID(idColumn) trick.
+func (MySQLDialect) Upsert(table string, columns []string, values []string, conflictColumns []string, updateColumns []string, idColumn string) (string, IDStrategy) {
+	var set []string
+	for _, col := range updateColumns {
+		set = append(set, col+" = VALUES("+col+")")
+	}
+	if idColumn != "" {
+		set = append(set, idColumn+" = LAST_INSERT_ID("+idColumn+")")
+	}
+	return "INSERT INTO " + table + " (" + strings.Join(columns, ", ") + ") VALUES (" + strings.Join(values, ", ") + ") ON DUPLICATE KEY UPDATE " + strings.Join(set, ", "), LastInsertID
+}
+
+// QuoteIdentifier implements Dialect.
+func (MySQLDialect) QuoteIdentifier(name string) string { return quoteIdentifier(name, "`", "`") }
+
+// MaxParams implements Dialect.
+func (MySQLDialect) MaxParams() int { return 65535 }
+
+// MaxInList implements Dialect.
+func (MySQLDialect) MaxInList() int { return 0 }
+
+// CaseInsensitiveEquality implements Dialect. Default collations are case-insensitive.
+func (MySQLDialect) CaseInsensitiveEquality() bool { return true }
+
+// BoolValue implements Dialect.
+func (MySQLDialect) BoolValue(b bool) interface{} { return b }
+
+// OracleDialect is Dialect for Oracle.
+type OracleDialect struct{}
+
+// DriverName implements Dialect.
+func (OracleDialect) DriverName() string { return "oracle" }
+
+// Placeholder implements Dialect.
+func (OracleDialect) Placeholder(argsCounter int) string { return ":" + strconv.Itoa(argsCounter) }
+
+// NumberedPlaceholders implements Dialect. The driver binds arguments by position, not by number.
+func (OracleDialect) NumberedPlaceholders() bool { return false }
+
+// Pagination implements Dialect.
+func (OracleDialect) Pagination(limit int, offset int) (string, []interface{}) {
+	return " OFFSET ? ROWS FETCH NEXT ? ROWS ONLY", []interface{}{offset, limit}
+}
+
+// TextSearch implements Dialect.
+func (OracleDialect) TextSearch(val string) (string, bool) { return " LIKE ", true }
+
+// LikeCollation implements Dialect.
+func (OracleDialect) LikeCollation() string { return "COLLATE binary_ai " }
+
+// OrderCollation implements Dialect.
+func (OracleDialect) OrderCollation() string { return "" }
+
+// InsertReturningID implements Dialect.
+func (OracleDialect) InsertReturningID(table string, columns string, values string, idColumn string, outParam string) (string, IDStrategy) {
+	return "INSERT INTO " + table + " (" + columns + ") VALUES (" + values + ") RETURNING " + idColumn + " INTO " + outParam, OutParamID
+}
+
+// InsertRows implements Dialect. The statement is INSERT ALL, IDs of inserted rows are not returned.
+func (OracleDialect) InsertRows(table string, columns string, values []string, idColumn string) (string, bool) {
+	sq := "INSERT ALL"
+	for _, v := range values {
+		sq += " INTO " + table + " (" + columns + ") VALUES (" + v + ")"
+	}
+	return sq + " SELECT 1 FROM DUAL", false
+}
+
+// Upsert implements Dialect. The statement is MERGE, which can not return the ID, so it is selected by conflictColumns.
+func (OracleDialect) Upsert(table string, columns []string, values []string, conflictColumns []string, updateColumns []string, idColumn string) (string, IDStrategy) {
+	var source []string
+	for i := range columns {
+		source = append(source, values[i]+" "+columns[i])
+	}
+	return "MERGE INTO " + table + " target USING (SELECT " + strings.Join(source, ", ") + " FROM DUAL) source ON (" +
+		mergeCondition(conflictColumns) + ") WHEN MATCHED THEN UPDATE SET " + mergeSet(updateColumns) +
+		" WHEN NOT MATCHED THEN INSERT (" + strings.Join(columns, ", ") + ") VALUES (" + mergeValues(columns) + ")", SelectID
+}
+
+// QuoteIdentifier implements Dialect.
+func (OracleDialect) QuoteIdentifier(name string) string { return quoteIdentifier(name, `"`, `"`) }
+
+// MaxParams implements Dialect.
+func (OracleDialect) MaxParams() int { return 65535 }
+
+// MaxInList implements Dialect.
+func (OracleDialect) MaxInList() int { return 1000 }
+
+// CaseInsensitiveEquality implements Dialect.
+func (OracleDialect) CaseInsensitiveEquality() bool { return false }
+
+// BoolValue implements Dialect. There is no boolean type, 1 or 0 is used.
+func (OracleDialect) BoolValue(b bool) interface{} { return boolInt(b) }
+
+// PostgreSQLDialect is Dialect for PostgreSQL.
+type PostgreSQLDialect struct{}
+
+// DriverName implements Dialect.
+func (PostgreSQLDialect) DriverName() string { return "pgx" }
+
+// Placeholder implements Dialect.
+func (PostgreSQLDialect) Placeholder(argsCounter int) string { return "$" + strconv.Itoa(argsCounter) }
+
+// NumberedPlaceholders implements Dialect.
+func (PostgreSQLDialect) NumberedPlaceholders() bool { return true }
+
+// Pagination implements Dialect.
+func (PostgreSQLDialect) Pagination(limit int, offset int) (string, []interface{}) {
+	return " LIMIT ? OFFSET ?", []interface{}{limit, offset}
+}
+
+// TextSearch implements Dialect.
+func (PostgreSQLDialect) TextSearch(val string) (string, bool) { return " ILIKE ", false }
+
+// LikeCollation implements Dialect.
+func (PostgreSQLDialect) LikeCollation() string { return "" }
+
+// OrderCollation implements Dialect.
+func (PostgreSQLDialect) OrderCollation() string { return "" }
+
+// InsertReturningID implements Dialect.
+func (PostgreSQLDialect) InsertReturningID(table string, columns string, values string, idColumn string, outParam string) (string, IDStrategy) {
+	return "INSERT INTO " + table + " (" + columns + ") VALUES (" + values + ") RETURNING " + idColumn, QueryRowID
+}
+
+// InsertRows implements Dialect.
+func (PostgreSQLDialect) InsertRows(table string, columns string, values []string, idColumn string) (string, bool) {
+	return insertRowsReturning(table, columns, values, idColumn)
+}
+
+// Upsert implements Dialect.
+func (PostgreSQLDialect) Upsert(table string, columns []string, values []string, conflictColumns []string, updateColumns []string, idColumn string) (string, IDStrategy) {
+	return upsertOnConflict(table, columns, values, conflictColumns, updateColumns, idColumn)
+}
+
+// QuoteIdentifier implements Dialect.
+func (PostgreSQLDialect) QuoteIdentifier(name string) string { return quoteIdentifier(name, `"`, `"`) }
+
+// MaxParams implements Dialect.
+func (PostgreSQLDialect) MaxParams() int { return 65535 }
+
+// MaxInList implements Dialect.
+func (PostgreSQLDialect) MaxInList() int { return 0 }
+
+// CaseInsensitiveEquality implements Dialect.
+func (PostgreSQLDialect) CaseInsensitiveEquality() bool { return false }
+
+// BoolValue implements Dialect.
+func (PostgreSQLDialect) BoolValue(b bool) interface{} { return b }
+
+// CockroachDBDialect is Dialect for CockroachDB. It uses PostgreSQL wire protocol and driver.
+// IDs of inserted rows are returned with RETURNING clause, so any default of ID column may be used, e.g. unique_rowid() (which values do not fit into 32-bit int).
+type CockroachDBDialect struct {
+	PostgreSQLDialect
+}
+
+// MariaDBDialect is Dialect for MariaDB. It uses MySQL driver.
+type MariaDBDialect struct {
+	MySQLDialect
+}
+
+// InsertReturningID implements Dialect. MariaDB supports RETURNING clause since 10.5.
+func (MariaDBDialect) InsertReturningID(table string, columns string, values string, idColumn string, outParam string) (string, IDStrategy) {
+	return "INSERT INTO " + table + " (" + columns + ") VALUES (" + values + ") RETURNING " + idColumn, QueryRowID
+}
+
+// InsertRows implements Dialect.
+func (MariaDBDialect) InsertRows(table string, columns string, values []string, idColumn string) (string, bool) {
+	return insertRowsReturning(table, columns, values, idColumn)
+}
+
+// DuckDBDialect is Dialect for DuckDB, embedded analytical database. Its driver is not imported by this package.
+type DuckDBDialect struct{}
+
+// DriverName implements Dialect.
+func (DuckDBDialect) DriverName() string { return "duckdb" }
+
+// Placeholder implements Dialect.
+func (DuckDBDialect) Placeholder(argsCounter int) string { return "$" + strconv.Itoa(argsCounter) }
+
+// NumberedPlaceholders implements Dialect.
+func (DuckDBDialect) NumberedPlaceholders() bool { return true }
+
+// Pagination implements Dialect.
+func (DuckDBDialect) Pagination(limit int, offset int) (string, []interface{}) {
+	return " LIMIT ? OFFSET ?", []interface{}{limit, offset}
+}
+
+// TextSearch implements Dialect.
+func (DuckDBDialect) TextSearch(val string) (string, bool) { return " ILIKE ", false }
+
+// LikeCollation implements Dialect.
+func (DuckDBDialect) LikeCollation() string { return "" }
+
+// OrderCollation implements Dialect.
+func (DuckDBDialect) OrderCollation() string { return "" }
+
+// InsertReturningID implements Dialect.
+func (DuckDBDialect) InsertReturningID(table string, columns string, values string, idColumn string, outParam string) (string, IDStrategy) {
+	return "INSERT INTO " + table + " (" + columns + ") VALUES (" + values + ") RETURNING " + idColumn, QueryRowID
+}
+
+// InsertRows implements Dialect.
+func (DuckDBDialect) InsertRows(table string, columns string, values []string, idColumn string) (string, bool) {
+	return insertRowsReturning(table, columns, values, idColumn)
+}
+
+// Upsert implements Dialect.
+func (DuckDBDialect) Upsert(table string, columns []string, values []string, conflictColumns []string, updateColumns []string, idColumn string) (string, IDStrategy) {
+	return upsertOnConflict(table, columns, values, conflictColumns, updateColumns, idColumn)
+}
+
+// QuoteIdentifier implements Dialect.
+func (DuckDBDialect) QuoteIdentifier(name string) string { return quoteIdentifier(name, `"`, `"`) }
+
+// MaxParams implements Dialect.
+func (DuckDBDialect) MaxParams() int { return 0 }
+
+// MaxInList implements Dialect.
+func (DuckDBDialect) MaxInList() int { return 0 }
+
+// CaseInsensitiveEquality implements Dialect.
+func (DuckDBDialect) CaseInsensitiveEquality() bool { return false }
+
+// BoolValue implements Dialect.
+func (DuckDBDialect) BoolValue(b bool) interface{} { return b }
+
+// genericDialect is used for unknown database types.
+type genericDialect struct {
+	MySQLDialect
+}
+
+func (genericDialect) DriverName() string { return "" }
+
+func (genericDialect) QuoteIdentifier(name string) string { return quoteIdentifier(name, `"`, `"`) }
@@ -0,0 +1,178 @@
+package sqla
+
+import (
+	"testing"
+)
+
+type testDialect struct {
+	PostgreSQLDialect
+}
+
+func (testDialect) Placeholder(argsCounter int) string { return "?" }
+
+func (testDialect) NumberedPlaceholders() bool { return false }
+
+func TestRegisterDialect(t *testing.T) {
+	const TESTDB = 100
+	RegisterDialect(TESTDB, testDialect{}, "testdb")
+	if ReturnDBType("testdb") != TESTDB {
+		t.Errorf("Expected:%d, received:%d", TESTDB, ReturnDBType("testdb"))
+	}
+	if MakeParam(TESTDB, 3) != "?" {
+		t.Errorf("Expected:%s, received:%s", "?", MakeParam(TESTDB, 3))
+	}
+	sq, _, _, _ := ConstructSELECTquery(TESTDB, "books", "*", "*", "", Filter{TextFilter: "x", TextFilterColumns: []string{"Title"}}, "", 0, 10, 0, false, Seek{})
+	expected := "SELECT * FROM books  WHERE (Title ILIKE ? )  LIMIT ? OFFSET ?"
+	if sq != expected {
+		t.Errorf("Expected:%s, received:%s", expected, sq)
+	}
+}
+
+func TestQuoteIdentifier(t *testing.T) {
+	tests := []struct {
+		DBType   byte
+		name     string
+		expected string
+	}{
+		{SQLITE, "books.Title", `"books"."Title"`},
+		{MSSQL, "dbo.books", `[dbo].[books]`},
+		{MSSQL, "odd]name", `[odd]]name]`},
+		{MYSQL, "books.*", "`books`.*"},
+		{ORACLE, "Title", `"Title"`},
+		{POSTGRESQL, `odd"name`, `"odd""name"`},
+	}
+	for _, tt := range tests {
+		if res := GetDialect(tt.DBType).QuoteIdentifier(tt.name); res != tt.expected {
+			t.Errorf("Expected:%s, received:%s", tt.expected, res)
+		}
+	}
+}
+
+func TestInsertReturningID(t *testing.T) {
+	tests := []struct {
+		DBType   byte
+		expected string
+		strategy IDStrategy
+	}{
+		{SQLITE, "INSERT INTO books (Title, Author) VALUES ($1, $2)", LastInsertID},
+		{MYSQL, "INSERT INTO books (Title, Author) VALUES (?, ?)", LastInsertID},
+		{ORACLE, "INSERT INTO books (Title, Author) VALUES (:1, :2) RETURNING ID INTO :3", OutParamID},
+		{POSTGRESQL, "INSERT INTO books (Title, Author) VALUES ($1, $2) RETURNING ID", QueryRowID},
+	}
+	for _, tt := range tests {
+		d := GetDialect(tt.DBType)
+		values := d.Placeholder(1) + ", " + d.Placeholder(2)
+		sq, strategy := d.InsertReturningID("books", "Title, Author", values, "ID", d.Placeholder(3))
+		if sq != tt.expected || strategy != tt.strategy {
+			t.Errorf("Expected:%s (%d), received:%s (%d)", tt.expected, tt.strategy, sq, strategy)
+		}
+	}
+}
+
+func TestInsertRows(t *testing.T) {
+	tests := []struct {
+		DBType     byte
+		idColumn   string
+		expected   string
+		returnsIDs bool
+	}{
+		{SQLITE, "ID", "INSERT INTO books (Title, Author) VALUES ($1, $2), ($3, $4) RETURNING ID", true},
+		{MYSQL, "ID", "INSERT INTO books (Title, Author) VALUES (?, ?), (?, ?)", false},
+		{ORACLE, "ID", "INSERT ALL INTO books (Title, Author) VALUES (:1, :2) INTO books (Title, Author) VALUES (:3, :4) SELECT 1 FROM DUAL", false},
+		{POSTGRESQL, "", "INSERT INTO books (Title, Author) VALUES ($1, $2), ($3, $4)", false},
+	}
+	for _, tt := range tests {
+		d := GetDialect(tt.DBType)
+		values := []string{d.Placeholder(1) + ", " + d.Placeholder(2), d.Placeholder(3) + ", " + d.Placeholder(4)}
+		sq, returnsIDs := d.InsertRows("books", "Title, Author", values, tt.idColumn)
+		if sq != tt.expected || returnsIDs != tt.returnsIDs {
+			t.Errorf("Expected:%s (%t), received:%s (%t)", tt.expected, tt.returnsIDs, sq, returnsIDs)
+		}
+	}
+}
+
+func TestUpsert(t *testing.T) {
+	tests := []struct {
+		DBType   byte
+		expected string
+		strategy IDStrategy
+	}{
+		{POSTGRESQL, "INSERT INTO settings (Name, Value) VALUES ($1, $2) ON CONFLICT (Name) DO UPDATE SET Value = EXCLUDED.Value RETURNING ID", QueryRowID},
+		{MYSQL, "INSERT INTO settings (Name, Value) VALUES (?, ?) ON DUPLICATE KEY UPDATE Value = VALUES(Value), ID = LAST_INSERT_ID(ID)", LastInsertID},
+		{ORACLE, "MERGE INTO settings target USING (SELECT :1 Name, :2 Value FROM DUAL) source ON (target.Name = source.Name) " +
+			"WHEN MATCHED THEN UPDATE SET target.Value = source.Value WHEN NOT MATCHED THEN INSERT (Name, Value) VALUES (source.Name, source.Value)", SelectID},
+	}
+	for _, tt := range tests {
+		d := GetDialect(tt.DBType)
+		values := []string{d.Placeholder(1), d.Placeholder(2)}
+		sq, strategy := d.Upsert("settings", []string{"Name", "Value"}, values, []string{"Name"}, []string{"Value"}, "ID")
+		if sq != tt.expected || strategy != tt.strategy {
+			t.Errorf("Expected:%s (%d), received:%s (%d)", tt.expected, tt.strategy, sq, strategy)
+		}
+	}
+}
+
+func TestNewDialectsGolden(t *testing.T) {
+	tests := []struct {
+		DBType  byte
+		sq      string
+		sqcount string
+	}{
+		{COCKROACHDB, "SELECT DISTINCT ID, RegNo FROM documents LEFT JOIN users ON users.ID = documents.Creator WHERE DocType IN ($1, $2) AND (Tags LIKE $3 OR Tags LIKE $4 OR Tags LIKE $5 OR Tags LIKE $6) AND (Creator IN ($7, $8) OR Assignee IN ($9, $10) ) AND (Created IS NULL OR Created <> $11) AND Sum BETWEEN $12 AND $13 AND Currency = $14 AND (About ILIKE $15 OR Note ILIKE $15 ) AND RegDate > $16 ORDER BY RegDate ASC  LIMIT $17 OFFSET $18",
+			"SELECT COUNT(DISTINCT ID) FROM documents LEFT JOIN users ON users.ID = documents.Creator WHERE DocType IN ($1, $2) AND (Tags LIKE $3 OR Tags LIKE $4 OR Tags LIKE $5 OR Tags LIKE $6) AND (Creator IN ($7, $8) OR Assignee IN ($9, $10) ) AND (Created IS NULL OR Created <> $11) AND Sum BETWEEN $12 AND $13 AND Currency = $14 AND (About ILIKE $15 OR Note ILIKE $15 ) "},
+		{MARIADB, "SELECT DISTINCT ID, RegNo FROM documents LEFT JOIN users ON users.ID = documents.Creator WHERE DocType IN (?, ?) AND (Tags LIKE ? OR Tags LIKE ? OR Tags LIKE ? OR Tags LIKE ?) AND (Creator IN (?, ?) OR Assignee IN (?, ?) ) AND (Created IS NULL OR Created <> ?) AND Sum BETWEEN ? AND ? AND Currency = ? AND (About LIKE ? OR Note LIKE ? ) AND RegDate > ? ORDER BY RegDate ASC  LIMIT ? OFFSET ?",
+			"SELECT COUNT(DISTINCT ID) FROM documents LEFT JOIN users ON users.ID = documents.Creator WHERE DocType IN (?, ?) AND (Tags LIKE ? OR Tags LIKE ? OR Tags LIKE ? OR Tags LIKE ?) AND (Creator IN (?, ?) OR Assignee IN (?, ?) ) AND (Created IS NULL OR Created <> ?) AND Sum BETWEEN ? AND ? AND Currency = ? AND (About LIKE ? OR Note LIKE ? ) "},
+		{DUCKDB, "SELECT DISTINCT ID, RegNo FROM documents LEFT JOIN users ON users.ID = documents.Creator WHERE DocType IN ($1, $2) AND (Tags LIKE $3 OR Tags LIKE $4 OR Tags LIKE $5 OR Tags LIKE $6) AND (Creator IN ($7, $8) OR Assignee IN ($9, $10) ) AND (Created IS NULL OR Created <> $11) AND Sum BETWEEN $12 AND $13 AND Currency = $14 AND (About ILIKE $15 OR Note ILIKE $15 ) AND RegDate > $16 ORDER BY RegDate ASC  LIMIT $17 OFFSET $18",
+			"SELECT COUNT(DISTINCT ID) FROM documents LEFT JOIN users ON users.ID = documents.Creator WHERE DocType IN ($1, $2) AND (Tags LIKE $3 OR Tags LIKE $4 OR Tags LIKE $5 OR Tags LIKE $6) AND (Creator IN ($7, $8) OR Assignee IN ($9, $10) ) AND (Created IS NULL OR Created <> $11) AND Sum BETWEEN $12 AND $13 AND Currency = $14 AND (About ILIKE $15 OR Note ILIKE $15 ) "},
+	}
+	for _, tt := range tests {
+		sq, sqcount, _, _ := ConstructSELECTquery(tt.DBType, "documents", "ID, RegNo", "ID", "LEFT JOIN users ON users.ID = documents.Creator",
+			testFilter(), "RegDate", 1, 20, 40, true, Seek{UseSeek: true, Value: 5})
+		if sq != tt.sq {
+			t.Errorf("Expected:%s, received:%s", tt.sq, sq)
+		}
+		if sqcount != tt.sqcount {
+			t.Errorf("Expected:%s, received:%s", tt.sqcount, sqcount)
+		}
+	}
+
+	insertTests := []struct {
+		DBType   byte
+		expected string
+		strategy IDStrategy
+	}{
+		{COCKROACHDB, "INSERT INTO books (Title, Author) VALUES ($1, $2) RETURNING ID", QueryRowID},
+		{MARIADB, "INSERT INTO books (Title, Author) VALUES (?, ?) RETURNING ID", QueryRowID},
+		{DUCKDB, "INSERT INTO books (Title, Author) VALUES ($1, $2) RETURNING ID", QueryRowID},
+	}
+	for _, tt := range insertTests {
+		d := GetDialect(tt.DBType)
+		values := d.Placeholder(1) + ", " + d.Placeholder(2)
+		sq, strategy := d.InsertReturningID("books", "Title, Author", values, "ID", d.Placeholder(3))
+		if sq != tt.expected || strategy != tt.strategy {
+			t.Errorf("Expected:%s (%d), received:%s (%d)", tt.expected, tt.strategy, sq, strategy)
+		}
+	}
+
+	dsnTests := []struct {
+		dbtype   string
+		DBType   byte
+		driver   string
+		expected string
+	}{
+		{"cockroach", COCKROACHDB, "pgx", "postgresql://root:@localhost:26257/defaultdb?sslmode=disable"},
+		{"mariadb", MARIADB, "mysql", "root:@tcp(localhost:26257)/defaultdb"},
+		{"duckdb", DUCKDB, "duckdb", "defaultdb"},
+	}
+	for _, tt := range dsnTests {
+		if ReturnDBType(tt.dbtype) != tt.DBType {
+			t.Errorf("Expected:%d, received:%d", tt.DBType, ReturnDBType(tt.dbtype))
+		}
+		if GetDialect(tt.DBType).DriverName() != tt.driver {
+			t.Errorf("Expected:%s, received:%s", tt.driver, GetDialect(tt.DBType).DriverName())
+		}
+		if dsn := BuildDSN(tt.dbtype, "defaultdb", "localhost", "26257", "root", ""); dsn != tt.expected {
+			t.Errorf("Expected:%s, received:%s", tt.expected, dsn)
+		}
+	}
+}
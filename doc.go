@@ -7,7 +7,8 @@
 // Standard Go database/sql functions are not changed.
 // All new functions works with them, and usual database/sql should be used when necessary.
 //
-// The package supports the following RDBMS: SQLite, Microsoft SQL Server, MySQL(MariaDB), Oracle, PostgreSQL.
+// The package supports the following RDBMS: SQLite, Microsoft SQL Server, MySQL, MariaDB, Oracle, PostgreSQL, CockroachDB, DuckDB.
+// Specifics of each RDBMS are described by Dialect, other dialects may be added or existing ones tweaked with RegisterDialect.
 //
 // The key functions of this package are related to the following:
 // working with different RDBMS seamlessly;
@@ -0,0 +1,286 @@
+package sqla
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	mssql "github.com/denisenkom/go-mssqldb"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgconn"
+	"github.com/mattn/go-sqlite3"
+	"github.com/sijms/go-ora/v2/network"
+)
+
+// These errors classify failures reported by different database drivers.
+// Errors returned by the functions of this package may be compared with them using errors.Is.
+// To get table, column or constraint name use errors.As with *Error.
+var (
+	ErrUniqueViolation     = errors.New("unique constraint violation")
+	ErrForeignKeyViolation = errors.New("foreign key constraint violation")
+	ErrNotNullViolation    = errors.New("not null constraint violation")
+	ErrCheckViolation      = errors.New("check constraint violation")
+	ErrDeadlock            = errors.New("deadlock detected")
+	ErrSerialization       = errors.New("serialization failure")
+)
+
+// Error is a classified database error. Kind is one of the sentinel errors above, Err is the original driver error.
+// Table, Column and Constraint are filled only if the driver exposes them (directly or in its error message).
+type Error struct {
+	Kind       error
+	Table      string
+	Column     string
+	Constraint string
+	Err        error
+}
+
+func (e *Error) Error() string {
+	var details []string
+	if e.Table != "" {
+		details = append(details, "table "+e.Table)
+	}
+	if e.Column != "" {
+		details = append(details, "column "+e.Column)
+	}
+	if e.Constraint != "" {
+		details = append(details, "constraint "+e.Constraint)
+	}
+	if len(details) > 0 {
+		return e.Kind.Error() + " (" + strings.Join(details, ", ") + "): " + e.Err.Error()
+	}
+	return e.Kind.Error() + ": " + e.Err.Error()
+}
+
+// Unwrap returns the original driver error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports if the error is of the kind of target.
+func (e *Error) Is(target error) bool {
+	return e.Kind == target
+}
+
+// ClassifyError translates a driver error into *Error if the error is a known constraint violation, deadlock or serialization failure.
+// Other errors are returned unchanged. The functions of this package classify errors automatically,
+// use ClassifyError for errors returned by standard database/sql calls.
+func ClassifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var ce *Error
+	if errors.As(err, &ce) {
+		return err
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return classifyPostgreSQL(pgErr, err)
+	}
+	var msErr mssql.Error
+	if errors.As(err, &msErr) {
+		return classifyMSSQL(msErr, err)
+	}
+	var myErr *mysql.MySQLError
+	if errors.As(err, &myErr) {
+		return classifyMySQL(myErr, err)
+	}
+	var oraErr *network.OracleError
+	if errors.As(err, &oraErr) {
+		return classifyOracle(oraErr, err)
+	}
+	var liteErr sqlite3.Error
+	if errors.As(err, &liteErr) {
+		return classifySQLite(liteErr, err)
+	}
+	return err
+}
+
+// wrapError classifies err and prefixes it with the name of the function (fname) where it happened.
+func wrapError(fname string, err error) error {
+	return fmt.Errorf("%s: %w", fname, ClassifyError(err))
+}
+
+func classifyPostgreSQL(pgErr *pgconn.PgError, err error) error {
+	var kind error
+	switch pgErr.Code {
+	case "23505":
+		kind = ErrUniqueViolation
+	case "23503":
+		kind = ErrForeignKeyViolation
+	case "23502":
+		kind = ErrNotNullViolation
+	case "23514":
+		kind = ErrCheckViolation
+	case "40P01":
+		kind = ErrDeadlock
+	case "40001":
+		kind = ErrSerialization
+	default:
+		return err
+	}
+	return &Error{Kind: kind, Table: pgErr.TableName, Column: pgErr.ColumnName, Constraint: pgErr.ConstraintName, Err: err}
+}
+
+var (
+	msConstraintRegExp = regexp.MustCompile(`constraint '([^']+)'|constraint "([^"]+)"`)
+	msTableRegExp      = regexp.MustCompile(`table "([^"]+)"|table '([^']+)'|object '([^']+)'`)
+	msColumnRegExp     = regexp.MustCompile(`column '([^']+)'`)
+	msNullTableRegExp  = regexp.MustCompile(`table '([^']+)'`)
+)
+
+func classifyMSSQL(msErr mssql.Error, err error) error {
+	e := &Error{Err: err}
+	msg := msErr.Message
+	switch msErr.Number {
+	case 2627, 2601:
+		e.Kind = ErrUniqueViolation
+	case 547:
+		if strings.Contains(msg, "CHECK") {
+			e.Kind = ErrCheckViolation
+		} else {
+			e.Kind = ErrForeignKeyViolation
+		}
+	case 515:
+		e.Kind = ErrNotNullViolation
+		// Cannot insert the value NULL into column 'c', table 'db.dbo.t'; column does not allow nulls.
+		e.Table = firstSubmatch(msNullTableRegExp, msg)
+	case 1205:
+		e.Kind = ErrDeadlock
+		return e
+	case 3960:
+		e.Kind = ErrSerialization
+		return e
+	default:
+		return err
+	}
+	e.Constraint = firstSubmatch(msConstraintRegExp, msg)
+	if e.Table == "" {
+		e.Table = firstSubmatch(msTableRegExp, msg)
+	}
+	e.Column = firstSubmatch(msColumnRegExp, msg)
+	return e
+}
+
+var (
+	myDupKeyRegExp = regexp.MustCompile("for key '([^']+)'")
+	myFKRegExp     = regexp.MustCompile("\\(`[^`]*`\\.`([^`]+)`, CONSTRAINT `([^`]+)` FOREIGN KEY \\(`([^`]+)`\\)")
+	myColumnRegExp = regexp.MustCompile("(?:Column|Field) '([^']+)'")
+	myCheckRegExp  = regexp.MustCompile("[Cc]heck constraint '([^']+)'|CONSTRAINT `([^`]+)` failed for `[^`]*`\\.`([^`]+)`")
+)
+
+func classifyMySQL(myErr *mysql.MySQLError, err error) error {
+	e := &Error{Err: err}
+	msg := myErr.Message
+	switch myErr.Number {
+	case 1062, 1586:
+		e.Kind = ErrUniqueViolation
+		// Duplicate entry 'x' for key 'table.key_name' (MySQL 8 prefixes key name with table name)
+		key := firstSubmatch(myDupKeyRegExp, msg)
+		if i := strings.LastIndex(key, "."); i >= 0 {
+			e.Table = key[:i]
+			key = key[i+1:]
+		}
+		e.Constraint = key
+	case 1451, 1452, 1216, 1217:
+		e.Kind = ErrForeignKeyViolation
+		if m := myFKRegExp.FindStringSubmatch(msg); m != nil {
+			e.Table, e.Constraint, e.Column = m[1], m[2], m[3]
+		}
+	case 1048, 1364:
+		e.Kind = ErrNotNullViolation
+		e.Column = firstSubmatch(myColumnRegExp, msg)
+	case 3819, 4025:
+		e.Kind = ErrCheckViolation
+		if m := myCheckRegExp.FindStringSubmatch(msg); m != nil {
+			e.Constraint = m[1] + m[2]
+			e.Table = m[3]
+		}
+	case 1213:
+		e.Kind = ErrDeadlock
+	default:
+		return err
+	}
+	return e
+}
+
+var (
+	oraConstraintRegExp = regexp.MustCompile(`constraint \(([^)]+)\)`)
+	oraNullRegExp       = regexp.MustCompile(`NULL (?:into )?\("([^"]+)"\."([^"]+)"\."([^"]+)"\)`)
+)
+
+func classifyOracle(oraErr *network.OracleError, err error) error {
+	e := &Error{Err: err}
+	msg := oraErr.Error()
+	switch oraErr.ErrCode {
+	case 1:
+		e.Kind = ErrUniqueViolation
+	case 2291, 2292:
+		e.Kind = ErrForeignKeyViolation
+	case 1400, 1407:
+		e.Kind = ErrNotNullViolation
+		// ORA-01400: cannot insert NULL into ("SCHEMA"."TABLE"."COLUMN")
+		if m := oraNullRegExp.FindStringSubmatch(msg); m != nil {
+			e.Table, e.Column = m[2], m[3]
+		}
+		return e
+	case 2290:
+		e.Kind = ErrCheckViolation
+	case 60:
+		e.Kind = ErrDeadlock
+		return e
+	case 8177:
+		e.Kind = ErrSerialization
+		return e
+	default:
+		return err
+	}
+	// constraint names are reported as SCHEMA.NAME
+	e.Constraint = firstSubmatch(oraConstraintRegExp, msg)
+	return e
+}
+
+func classifySQLite(liteErr sqlite3.Error, err error) error {
+	e := &Error{Err: err}
+	switch liteErr.ExtendedCode {
+	case sqlite3.ErrConstraintUnique, sqlite3.ErrConstraintPrimaryKey:
+		e.Kind = ErrUniqueViolation
+	case sqlite3.ErrConstraintForeignKey:
+		e.Kind = ErrForeignKeyViolation
+		return e
+	case sqlite3.ErrConstraintNotNull:
+		e.Kind = ErrNotNullViolation
+	case sqlite3.ErrConstraintCheck:
+		e.Kind = ErrCheckViolation
+		// CHECK constraint failed: name
+		if i := strings.LastIndex(liteErr.Error(), ": "); i >= 0 {
+			e.Constraint = liteErr.Error()[i+2:]
+		}
+		return e
+	default:
+		return err
+	}
+	// UNIQUE constraint failed: table.column, table.column2
+	msg := liteErr.Error()
+	if i := strings.LastIndex(msg, ": "); i >= 0 {
+		cols := strings.Split(msg[i+2:], ", ")
+		for j, col := range cols {
+			if k := strings.Index(col, "."); k >= 0 {
+				e.Table = col[:k]
+				cols[j] = col[k+1:]
+			}
+		}
+		e.Column = strings.Join(cols, ", ")
+	}
+	return e
+}
+
+func firstSubmatch(re *regexp.Regexp, s string) string {
+	m := re.FindStringSubmatch(s)
+	for i := 1; i < len(m); i++ {
+		if m[i] != "" {
+			return m[i]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,70 @@
+package sqla
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgconn"
+)
+
+func TestClassifyErrorSQLite(t *testing.T) {
+	var DBType byte
+	DBType = SQLITE
+	var db *sql.DB
+	db = OpenSQLConnection(DBType, "file:errtest?mode=memory&cache=shared&_foreign_keys=true")
+	defer db.Close()
+	db.Exec("CREATE TABLE authors (ID INTEGER PRIMARY KEY, Name TEXT NOT NULL UNIQUE);")
+	db.Exec("CREATE TABLE books (ID INTEGER PRIMARY KEY, Author INTEGER REFERENCES authors(ID));")
+
+	var args AnyTslice
+	args = args.AppendNonEmptyString("Name", "Lewis Carroll")
+	InsertObject(db, DBType, "authors", args)
+	_, _, err := InsertObjectContext(context.Background(), db, DBType, "authors", args)
+	var e *Error
+	if !errors.Is(err, ErrUniqueViolation) || !errors.As(err, &e) {
+		t.Fatalf("Expected:%v, received:%v", ErrUniqueViolation, err)
+	}
+	if e.Table != "authors" || e.Column != "Name" {
+		t.Errorf("Expected:%s, received:%s.%s", "authors.Name", e.Table, e.Column)
+	}
+
+	args = nil
+	args = args.AppendNil("Name")
+	_, _, err = InsertObjectContext(context.Background(), db, DBType, "authors", args)
+	if !errors.Is(err, ErrNotNullViolation) {
+		t.Errorf("Expected:%v, received:%v", ErrNotNullViolation, err)
+	}
+
+	args = nil
+	args = args.AppendInt("Author", 999)
+	_, _, err = InsertObjectContext(context.Background(), db, DBType, "books", args)
+	if !errors.Is(err, ErrForeignKeyViolation) {
+		t.Errorf("Expected:%v, received:%v", ErrForeignKeyViolation, err)
+	}
+}
+
+func TestClassifyError(t *testing.T) {
+	err := ClassifyError(&pgconn.PgError{Code: "23503", TableName: "books", ConstraintName: "books_author_fkey"})
+	var e *Error
+	if !errors.Is(err, ErrForeignKeyViolation) || !errors.As(err, &e) || e.Constraint != "books_author_fkey" {
+		t.Errorf("Expected:%v, received:%v", ErrForeignKeyViolation, err)
+	}
+
+	err = ClassifyError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'x' for key 'authors.Name'"})
+	if !errors.Is(err, ErrUniqueViolation) || !errors.As(err, &e) || e.Table != "authors" || e.Constraint != "Name" {
+		t.Errorf("Expected:%v, received:%v", ErrUniqueViolation, err)
+	}
+
+	err = ClassifyError(&mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"})
+	if !errors.Is(err, ErrDeadlock) || !isRetryableTxError(err) {
+		t.Errorf("Expected:%v, received:%v", ErrDeadlock, err)
+	}
+
+	plain := errors.New("some error")
+	if ClassifyError(plain) != plain {
+		t.Errorf("Expected unclassified error to be returned unchanged")
+	}
+}
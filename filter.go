@@ -6,21 +6,34 @@ import (
 	"net/http"
 	"regexp"
 	"strconv"
+	"strings"
 )
 
 // Filter for a page. This Filter type is like a socket to connect database, backend, and even frontend parts.
 //
 // ClassFilter allows to filter by a list of sevaral integers.
 // ClassFilterOR has the same functionality, however is allows to put OR operator in SQL statement between different ClassFilterOR filters (which have the same name but different columns).
+// StringClassFilter and StringClassFilterOR do the same with lists of strings.
+// NullFilter and BoolFilter allow to filter by NULL and boolean values.
+// Predicate allows to combine conditions with AND, OR and NOT in any way, see Predicate type.
 // See descriptions of other filter types for details.
+// IncludeDeleted makes select statements include soft-deleted rows (see TableMeta.SoftDeleteColumn), it is never set from JSON or form.
+// Subject makes select statements include only the rows the subject may read according to the table Policy, it is never set from JSON or form either.
 type Filter struct {
-	ClassFilter       []ClassFilter
-	ClassFilterOR     []ClassFilter
-	DateFilter        []DateFilter
-	SumFilter         []SumFilter
-	TextFilterName    string
-	TextFilter        string
-	TextFilterColumns []string
+	ClassFilter         []ClassFilter
+	ClassFilterOR       []ClassFilter
+	StringClassFilter   []StringClassFilter
+	StringClassFilterOR []StringClassFilter
+	NullFilter          []NullFilter
+	BoolFilter          []BoolFilter
+	DateFilter          []DateFilter
+	SumFilter           []SumFilter
+	TextFilterName      string
+	TextFilter          string
+	TextFilterColumns   []string
+	Predicate           *Predicate
+	IncludeDeleted      bool     `json:"-"`
+	Subject             *Subject `json:"-"`
 }
 
 // ClassFilter to filter types, statuses, etc.
@@ -34,6 +47,35 @@ type ClassFilter struct {
 	List     []int
 }
 
+// StringClassFilter to filter statuses, codes, tags, etc. stored as text.
+// InJSON allows to search for a string value inside JSON list made by AppendJSONList. CaseInsensitive makes comparison case-insensitive.
+type StringClassFilter struct {
+	Name            string
+	Selector        string
+	InJSON          bool
+	CaseInsensitive bool
+	Column          string
+	List            []string
+}
+
+// NullFilter to filter empty or filled values, e.g. documents without assignee. Null selects rows where Column IS NULL if true, or IS NOT NULL if false.
+// InJSON makes empty JSON list '[]' (see AppendJSONList) treated the same as NULL.
+// In a form the value of the filter should be "true" (or "null") or "false" (or "notnull").
+type NullFilter struct {
+	Name   string
+	InJSON bool
+	Column string
+	Null   bool
+}
+
+// BoolFilter to filter flags, e.g. archived documents. Value is compared with Column, see Dialect.BoolValue for databases without boolean type.
+// In a form the value of the filter should be "true" (or "on") or "false".
+type BoolFilter struct {
+	Name   string
+	Column string
+	Value  bool
+}
+
 // DateFilter to filter dates and datetime.
 // Dates should be stored as timestamps with value type of int64.
 // DatesStr contains strings to represent values in user interface.
@@ -59,6 +101,7 @@ type SumFilter struct {
 }
 
 // GetFilterFromJSON unmarshals JSON to Filter struct and then only converts dates and sums from strings to integer representation.
+// Column names are taken from JSON as well, use FilterSchema.FilterFromJSON to take them from a schema declared on the server.
 // dateConvFunc and dateTimeConvFunc - are used to convert string-typed dates to int64-datestamps or int64-timestamps. These may be the same - it is a developer's choice.
 func (f *Filter) GetFilterFromJSON(JSON []byte,
 	dateConvFunc func(string) int64,
@@ -68,26 +111,45 @@ func (f *Filter) GetFilterFromJSON(JSON []byte,
 	if err != nil {
 		log.Println(err)
 	}
+	f.convertJSONValues(dateConvFunc, dateTimeConvFunc)
+}
 
-	dtRegExp := regexp.MustCompile("-[0-9]{1,2}[T ][0-9]{1,2}:[0-9]")
-	for i, df := range f.DateFilter {
-		df.Dates = nil
-		for i := range df.DatesStr {
-			if dtRegExp.MatchString(df.DatesStr[i]) {
-				df.Dates = append(df.Dates, dateConvFunc(df.DatesStr[i]))
-			} else {
-				df.Dates = append(df.Dates, dateTimeConvFunc(df.DatesStr[i]))
+// convertJSONValues converts dates and sums received as strings to integer representation.
+func (f *Filter) convertJSONValues(dateConvFunc func(string) int64, dateTimeConvFunc func(string) int64) {
+	for i := range f.DateFilter {
+		f.DateFilter[i].convertJSONDates(dateConvFunc, dateTimeConvFunc)
+	}
+	for i := range f.SumFilter {
+		f.SumFilter[i].convertJSONSums()
+	}
+	if f.Predicate != nil {
+		f.Predicate.walk(func(leaf *Predicate) {
+			if leaf.Date != nil {
+				leaf.Date.convertJSONDates(dateConvFunc, dateTimeConvFunc)
 			}
-		}
-		f.DateFilter[i].Dates = df.Dates
+			if leaf.Sum != nil {
+				leaf.Sum.convertJSONSums()
+			}
+		})
 	}
+}
 
-	for i, sf := range f.SumFilter {
-		sf.Sums = nil
-		for i := range sf.SumsStr {
-			sf.Sums = append(sf.Sums, processJSONSum(sf.SumsStr[i]))
+func (df *DateFilter) convertJSONDates(dateConvFunc func(string) int64, dateTimeConvFunc func(string) int64) {
+	dtRegExp := regexp.MustCompile("-[0-9]{1,2}[T ][0-9]{1,2}:[0-9]")
+	df.Dates = nil
+	for i := range df.DatesStr {
+		if dtRegExp.MatchString(df.DatesStr[i]) {
+			df.Dates = append(df.Dates, dateConvFunc(df.DatesStr[i]))
+		} else {
+			df.Dates = append(df.Dates, dateTimeConvFunc(df.DatesStr[i]))
 		}
-		f.SumFilter[i].Sums = sf.Sums
+	}
+}
+
+func (sf *SumFilter) convertJSONSums() {
+	sf.Sums = nil
+	for i := range sf.SumsStr {
+		sf.Sums = append(sf.Sums, processJSONSum(sf.SumsStr[i]))
 	}
 }
 
@@ -143,6 +205,52 @@ func (f *Filter) GetFilterFromForm(r *http.Request,
 	}
 	f.ClassFilterOR = cfListToReplace
 
+	scfListToReplace := []StringClassFilter{}
+	for i := range f.StringClassFilter {
+		if classes := nonEmptyStrings(r.Form[f.StringClassFilter[i].Name]); len(classes) > 0 {
+			f.StringClassFilter[i].List = classes
+			scfListToReplace = append(scfListToReplace, f.StringClassFilter[i])
+		}
+	}
+	f.StringClassFilter = scfListToReplace
+
+	scfListToReplace = nil
+	for i := range f.StringClassFilterOR {
+		if classes := nonEmptyStrings(r.Form[f.StringClassFilterOR[i].Name]); len(classes) > 0 {
+			f.StringClassFilterOR[i].List = classes
+			scfListToReplace = append(scfListToReplace, f.StringClassFilterOR[i])
+		}
+	}
+	f.StringClassFilterOR = scfListToReplace
+
+	nfListToReplace := []NullFilter{}
+	for i := range f.NullFilter {
+		switch strings.ToLower(r.FormValue(f.NullFilter[i].Name)) {
+		case "true", "1", "null":
+			f.NullFilter[i].Null = true
+		case "false", "0", "notnull":
+			f.NullFilter[i].Null = false
+		default:
+			continue
+		}
+		nfListToReplace = append(nfListToReplace, f.NullFilter[i])
+	}
+	f.NullFilter = nfListToReplace
+
+	bfListToReplace := []BoolFilter{}
+	for i := range f.BoolFilter {
+		switch strings.ToLower(r.FormValue(f.BoolFilter[i].Name)) {
+		case "true", "1", "on":
+			f.BoolFilter[i].Value = true
+		case "false", "0":
+			f.BoolFilter[i].Value = false
+		default:
+			continue
+		}
+		bfListToReplace = append(bfListToReplace, f.BoolFilter[i])
+	}
+	f.BoolFilter = bfListToReplace
+
 	dfListToReplace := []DateFilter{}
 
 	dtRegExp := regexp.MustCompile("-[0-9]{1,2}[T ][0-9]{1,2}:[0-9]")
@@ -207,6 +315,18 @@ func (f *Filter) ClearColumnsValues() {
 	for i := range f.ClassFilterOR {
 		f.ClassFilterOR[i].Column = ""
 	}
+	for i := range f.StringClassFilter {
+		f.StringClassFilter[i].Column = ""
+	}
+	for i := range f.StringClassFilterOR {
+		f.StringClassFilterOR[i].Column = ""
+	}
+	for i := range f.NullFilter {
+		f.NullFilter[i].Column = ""
+	}
+	for i := range f.BoolFilter {
+		f.BoolFilter[i].Column = ""
+	}
 	for i := range f.DateFilter {
 		f.DateFilter[i].Column = ""
 	}
@@ -215,4 +335,25 @@ func (f *Filter) ClearColumnsValues() {
 		f.SumFilter[i].CurrencyColumn = ""
 	}
 	f.TextFilterColumns = []string{}
+	if f.Predicate != nil {
+		f.Predicate.walk(func(leaf *Predicate) {
+			switch {
+			case leaf.Class != nil:
+				leaf.Class.Column = ""
+			case leaf.StringClass != nil:
+				leaf.StringClass.Column = ""
+			case leaf.Null != nil:
+				leaf.Null.Column = ""
+			case leaf.Bool != nil:
+				leaf.Bool.Column = ""
+			case leaf.Date != nil:
+				leaf.Date.Column = ""
+			case leaf.Sum != nil:
+				leaf.Sum.Column = ""
+				leaf.Sum.CurrencyColumn = ""
+			case leaf.Text != nil:
+				leaf.Text.Columns = []string{}
+			}
+		})
+	}
 }
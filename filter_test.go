@@ -1,6 +1,9 @@
 package sqla
 
 import (
+	"database/sql"
+	"errors"
+	"net/http/httptest"
 	"strconv"
 	"strings"
 	"testing"
@@ -64,3 +67,194 @@ func TestGetFilterFromJSON(t *testing.T) {
 		t.Errorf("Expected:%d, received:%d", 100000, f.SumFilter[0].Sums[1])
 	}
 }
+
+func TestFilterSchema(t *testing.T) {
+	schema := FilterSchema{
+		{Name: "doctypes", Kind: FilterClass, Column: "DocType", Min: 0, Max: 10},
+		{Name: "creatorsORassignees", Kind: FilterClassOR, Selector: "userSelector", Column: "Creator"},
+		{Name: "creatorsORassignees", Kind: FilterClassOR, Selector: "userSelector", Column: "Assignee"},
+		{Name: "createdDates", Kind: FilterDate, Column: "Created", Relations: []string{"eq", "gt"}},
+		{Name: "sums", Kind: FilterSum, Column: "Sum", CurrencyColumn: "Currency"},
+		{Name: "search", Kind: FilterText, Columns: []string{"About", "Note"}},
+	}
+	JSON := `{
+"ClassFilter":[{"Name":"doctypes","Column":"1=1 OR DocType","List":[0,1,2]},{"Name":"secrets","Column":"Secret","List":[1]}],
+"ClassFilterOR":[{"Name":"creatorsORassignees","Column":"Creators","List":[7,8,9]},{"Name":"creatorsORassignees","Column":"Assignees","List":[7,8,9]}],
+"DateFilter":[{"Name":"createdDates","Column":"Created","Relation":"<","DatesStr":["2022-02-01T08:47"]}],
+"SumFilter":[{"Name":"sums","Column":"Sum","CurrencyColumn":"Currency","CurrencyCode":840,"SumsStr":["0.00","1000.00"]}],
+"TextFilter":"testphrase","TextFilterColumns":["Password"]
+}
+`
+	f, err := schema.FilterFromJSON([]byte(JSON), dateTimeToInt64, dateTimeToInt64)
+	var errs FilterErrors
+	if !errors.As(err, &errs) || len(errs) != 2 || !errors.Is(err, ErrUnknownFilter) || !errors.Is(err, ErrFilterRelation) {
+		t.Errorf("Expected:%s and %s, received:%v", ErrUnknownFilter, ErrFilterRelation, err)
+	}
+	if len(f.ClassFilter) != 1 || f.ClassFilter[0].Column != "DocType" {
+		t.Errorf("Expected:%s, received:%#v", "DocType", f.ClassFilter)
+	}
+	if len(f.ClassFilterOR) != 2 || f.ClassFilterOR[1].Column != "Assignee" || len(f.ClassFilterOR[1].List) != 3 {
+		t.Errorf("Expected:%s, received:%#v", "Assignee", f.ClassFilterOR)
+	}
+	if len(f.DateFilter) != 0 || len(f.SumFilter) != 1 || f.SumFilter[0].Sums[1] != 100000 {
+		t.Errorf("Expected:%d, received:%#v", 100000, f.SumFilter)
+	}
+	if strings.Join(f.TextFilterColumns, ",") != "About,Note" {
+		t.Errorf("Expected:%s, received:%s", "About,Note", strings.Join(f.TextFilterColumns, ","))
+	}
+
+	JSON = `{"Predicate":{"Op":"or","Args":[{"Class":{"Name":"creatorsORassignees","Column":"Password","List":[7]}},{"Text":{"Name":"search","Text":"x"}}]}}`
+	f, err = schema.FilterFromJSON([]byte(JSON), dateTimeToInt64, dateTimeToInt64)
+	if err != nil || f.Predicate == nil || f.Predicate.Args[0].Class.Column != "Creator" || len(f.Predicate.Args[1].Text.Columns) != 2 {
+		t.Errorf("Expected:%s, received:%v, %#v", "Creator", err, f.Predicate)
+	}
+	JSON = `{"Predicate":{"Op":"not","Args":[{"Class":{"Name":"secrets","Column":"Secret","List":[1]}}]}}`
+	if f, err = schema.FilterFromJSON([]byte(JSON), dateTimeToInt64, dateTimeToInt64); !errors.Is(err, ErrUnknownFilter) || f.Predicate != nil {
+		t.Errorf("Expected:%s, received:%v, %#v", ErrUnknownFilter, err, f.Predicate)
+	}
+
+	r := httptest.NewRequest("GET", "/?doctypes=1&doctypes=20&createdDates=2022-02-01T08:47&createdDatesRelation=gt&search=x&page=2", nil)
+	f, err = schema.FilterFromForm(r, dateTimeToInt64, dateTimeToInt64, nil)
+	if !errors.Is(err, ErrFilterValue) || len(f.ClassFilter) != 0 || len(f.DateFilter) != 1 || f.TextFilter != "x" {
+		t.Errorf("Expected:%s, received:%v, %#v", ErrFilterValue, err, f)
+	}
+}
+
+func TestStringClassFilter(t *testing.T) {
+	var DBType byte
+	DBType = SQLITE
+	var db *sql.DB
+	db = OpenSQLConnection(DBType, "file:stringclasstest?mode=memory&cache=shared")
+	defer db.Close()
+	db.Exec("CREATE TABLE articles (ID INTEGER PRIMARY KEY, Country TEXT, Status TEXT, Tags TEXT);")
+	for _, row := range [][3]string{{"US", "draft", "go"}, {"de", "Published", "sql"}, {"FR", "published", "100%"}} {
+		var args AnyTslice
+		args = args.AppendNonEmptyString("Country", row[0])
+		args = args.AppendNonEmptyString("Status", row[1])
+		args = args.AppendJSONList("Tags", []string{row[2], "news"})
+		InsertObject(db, DBType, "articles", args)
+	}
+	count := func(F Filter) (n int) {
+		sq, args, err := NewSelectBuilder(DBType).From("articles").Count("*").Where(F).BuildCount()
+		if err == nil {
+			err = db.QueryRow(sq, args...).Scan(&n)
+		}
+		if err != nil {
+			t.Error(err)
+		}
+		return n
+	}
+
+	r := httptest.NewRequest("GET", "/?countries=US&countries=DE&countries=&status=PUBLISHED&tags=100%25", nil)
+	F := Filter{
+		StringClassFilter: []StringClassFilter{{Name: "countries", Column: "Country", CaseInsensitive: true}},
+	}
+	F.GetFilterFromForm(r, dateTimeToInt64, dateTimeToInt64, nil)
+	if len(F.StringClassFilter[0].List) != 2 {
+		t.Errorf("Expected:%d, received:%d", 2, len(F.StringClassFilter[0].List))
+	}
+	if n := count(F); n != 2 {
+		t.Errorf("Expected:%d, received:%d", 2, n)
+	}
+	F.StringClassFilter[0].CaseInsensitive = false
+	if n := count(F); n != 1 {
+		t.Errorf("Expected:%d, received:%d", 1, n)
+	}
+
+	JSON := `{"StringClassFilterOR":[{"Name":"tagsORstatus","InJSON":true,"Column":"Tags","List":["100%","news"]},{"Name":"tagsORstatus","Column":"Status","List":["draft"]}]}`
+	F = Filter{}
+	F.GetFilterFromJSON([]byte(JSON), dateTimeToInt64, dateTimeToInt64)
+	if n := count(F); n != 3 {
+		t.Errorf("Expected:%d, received:%d", 3, n)
+	}
+	F.StringClassFilterOR[0].List = []string{"100%"}
+	F.StringClassFilterOR[1].List = []string{"none"}
+	if n := count(F); n != 1 {
+		t.Errorf("Expected:%d, received:%d", 1, n)
+	}
+
+	sq, args, _ := NewSelectBuilder(POSTGRESQL).From("articles").Where(Filter{StringClassFilter: []StringClassFilter{
+		{Column: "Status", CaseInsensitive: true, List: []string{"Draft"}},
+		{Column: "Tags", InJSON: true, List: []string{"a_b"}},
+	}}).Build()
+	expected := `SELECT * FROM articles  WHERE UPPER(Status) IN ($1) AND (Tags LIKE $2 ESCAPE '!') `
+	if sq != expected || args[0] != "DRAFT" || args[1] != `%"a!_b"%` {
+		t.Errorf("Expected:%s, received:%s, %v", expected, sq, args)
+	}
+	sq, args, _ = NewSelectBuilder(MYSQL).From("articles").Where(Filter{StringClassFilterOR: []StringClassFilter{
+		{Name: "tags", Column: "Tags", InJSON: true, CaseInsensitive: true, List: []string{"100%", "a!"}},
+	}}).Build()
+	expected = `SELECT * FROM articles  WHERE ((Tags LIKE ? ESCAPE '!' OR Tags LIKE ? ESCAPE '!') ) `
+	if sq != expected || args[0] != `%"100!%"%` || args[1] != `%"a!!"%` {
+		t.Errorf("Expected:%s, received:%s, %v", expected, sq, args)
+	}
+
+	if n := count(Filter{TextFilter: "100%", TextFilterColumns: []string{"Tags"}}); n != 1 {
+		t.Errorf("Expected:%d, received:%d", 1, n)
+	}
+
+	F.ClearColumnsValues()
+	if F.StringClassFilterOR[0].Column != "" {
+		t.Errorf("Expected:%s, received:%s", "", F.StringClassFilterOR[0].Column)
+	}
+}
+
+func TestNullBoolFilter(t *testing.T) {
+	var DBType byte
+	DBType = SQLITE
+	var db *sql.DB
+	db = OpenSQLConnection(DBType, "file:nullbooltest?mode=memory&cache=shared")
+	defer db.Close()
+	db.Exec("CREATE TABLE tasks (ID INTEGER PRIMARY KEY, Assignee INTEGER, Watchers TEXT, Archived INTEGER NOT NULL DEFAULT 0);")
+	db.Exec("INSERT INTO tasks (Assignee, Watchers, Archived) VALUES (1, '[2,3]', 0), (NULL, '[]', 1), (NULL, NULL, 0), (2, '[1]', 1);")
+	count := func(F Filter) (n int) {
+		sq, args, err := NewSelectBuilder(DBType).From("tasks").Count("*").Where(F).BuildCount()
+		if err == nil {
+			err = db.QueryRow(sq, args...).Scan(&n)
+		}
+		if err != nil {
+			t.Error(err)
+		}
+		return n
+	}
+
+	r := httptest.NewRequest("GET", "/?noassignee=true&archived=on&watchers=", nil)
+	F := Filter{
+		NullFilter: []NullFilter{{Name: "noassignee", Column: "Assignee"}, {Name: "watchers", Column: "Watchers", InJSON: true}},
+		BoolFilter: []BoolFilter{{Name: "archived", Column: "Archived"}},
+	}
+	F.GetFilterFromForm(r, dateTimeToInt64, dateTimeToInt64, nil)
+	if len(F.NullFilter) != 1 || len(F.BoolFilter) != 1 {
+		t.Errorf("Expected:%d, %d, received:%d, %d", 1, 1, len(F.NullFilter), len(F.BoolFilter))
+	}
+	if n := count(F); n != 1 {
+		t.Errorf("Expected:%d, received:%d", 1, n)
+	}
+
+	JSON := `{"NullFilter":[{"Name":"watchers","InJSON":true,"Column":"Watchers","Null":false}],"BoolFilter":[{"Name":"archived","Column":"Archived","Value":false}]}`
+	F = Filter{}
+	F.GetFilterFromJSON([]byte(JSON), dateTimeToInt64, dateTimeToInt64)
+	if n := count(F); n != 1 {
+		t.Errorf("Expected:%d, received:%d", 1, n)
+	}
+	F.NullFilter[0].Null = true
+	F.BoolFilter = nil
+	if n := count(F); n != 2 {
+		t.Errorf("Expected:%d, received:%d", 2, n)
+	}
+
+	var tests = []struct {
+		DBType   byte
+		expected interface{}
+	}{
+		{ORACLE, 1},
+		{MSSQL, 1},
+		{POSTGRESQL, true},
+	}
+	for _, tt := range tests {
+		_, args, _ := NewSelectBuilder(tt.DBType).From("tasks").Where(Filter{BoolFilter: []BoolFilter{{Column: "Archived", Value: true}}}).Build()
+		if len(args) != 1 || args[0] != tt.expected {
+			t.Errorf("Expected:%v, received:%v", tt.expected, args)
+		}
+	}
+}
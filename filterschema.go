@@ -0,0 +1,478 @@
+package sqla
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// FilterKind defines which part of Filter a FilterField makes.
+type FilterKind byte
+
+// FilterClass - ClassFilter; FilterClassOR - ClassFilterOR; FilterDate - DateFilter; FilterSum - SumFilter; FilterText - TextFilter;
+// FilterStringClass - StringClassFilter; FilterStringClassOR - StringClassFilterOR; FilterNull - NullFilter; FilterBool - BoolFilter.
+const (
+	FilterClass FilterKind = iota
+	FilterClassOR
+	FilterDate
+	FilterSum
+	FilterText
+	FilterStringClass
+	FilterStringClassOR
+	FilterNull
+	FilterBool
+)
+
+// FilterField declares on the server side a filter which a client may use.
+// Name is the only thing which connects a filter received from a client with the field, all columns are taken from the field.
+// Several FilterClassOR (or FilterStringClassOR) fields may have the same Name and different columns. Columns are used only by FilterText.
+//
+// Relations restrict relations of date and sum filters (e.g. "=", "gt"), if empty any relation is allowed.
+// Min and Max restrict values (class values, dates as converted to int64, sums multiplied by 100) if Max is greater than Min.
+type FilterField struct {
+	Name            string
+	Kind            FilterKind
+	Selector        string
+	InJSON          bool
+	CaseInsensitive bool
+	Column          string
+	CurrencyColumn  string
+	Columns         []string
+	Relations       []string
+	Min             int64
+	Max             int64
+}
+
+// FilterSchema is a list of filters allowed for a page. Use it instead of GetFilterFromJSON and GetFilterFromForm to make sure
+// that column names are never taken from a client.
+type FilterSchema []FilterField
+
+// ErrUnknownFilter, ErrFilterRelation and ErrFilterValue are wrapped by FilterError.
+var (
+	ErrUnknownFilter  = errors.New("unknown filter")
+	ErrFilterRelation = errors.New("relation is not allowed")
+	ErrFilterValue    = errors.New("value is not allowed")
+)
+
+// FilterError describes a filter received from a client which was rejected by FilterSchema.
+type FilterError struct {
+	Name string
+	Err  error
+}
+
+func (e FilterError) Error() string {
+	return "filter " + e.Name + ": " + e.Err.Error()
+}
+
+// Unwrap returns the reason of the error, e.g. ErrUnknownFilter.
+func (e FilterError) Unwrap() error {
+	return e.Err
+}
+
+// FilterErrors lists all the filters rejected by FilterSchema.
+type FilterErrors []FilterError
+
+func (e FilterErrors) Error() string {
+	var s []string
+	for _, fe := range e {
+		s = append(s, fe.Error())
+	}
+	return strings.Join(s, "; ")
+}
+
+// Is reports whether any of the errors matches target, so errors.Is(err, ErrUnknownFilter) may be used.
+func (e FilterErrors) Is(target error) bool {
+	for _, fe := range e {
+		if errors.Is(fe, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter returns Filter with all the filters of the schema without values, e.g. to render a form.
+func (s FilterSchema) Filter() (f Filter) {
+	for _, field := range s {
+		switch field.Kind {
+		case FilterClass:
+			f.ClassFilter = append(f.ClassFilter, ClassFilter{Name: field.Name, Selector: field.Selector, InJSON: field.InJSON, Column: field.Column})
+		case FilterClassOR:
+			f.ClassFilterOR = append(f.ClassFilterOR, ClassFilter{Name: field.Name, Selector: field.Selector, InJSON: field.InJSON, Column: field.Column})
+		case FilterStringClass:
+			f.StringClassFilter = append(f.StringClassFilter, StringClassFilter{Name: field.Name, Selector: field.Selector, InJSON: field.InJSON, CaseInsensitive: field.CaseInsensitive, Column: field.Column})
+		case FilterStringClassOR:
+			f.StringClassFilterOR = append(f.StringClassFilterOR, StringClassFilter{Name: field.Name, Selector: field.Selector, InJSON: field.InJSON, CaseInsensitive: field.CaseInsensitive, Column: field.Column})
+		case FilterNull:
+			f.NullFilter = append(f.NullFilter, NullFilter{Name: field.Name, InJSON: field.InJSON, Column: field.Column})
+		case FilterBool:
+			f.BoolFilter = append(f.BoolFilter, BoolFilter{Name: field.Name, Column: field.Column})
+		case FilterDate:
+			f.DateFilter = append(f.DateFilter, DateFilter{Name: field.Name, Column: field.Column})
+		case FilterSum:
+			f.SumFilter = append(f.SumFilter, SumFilter{Name: field.Name, Column: field.Column, CurrencyColumn: field.CurrencyColumn})
+		case FilterText:
+			if f.TextFilterName == "" {
+				f.TextFilterName = field.Name
+				f.TextFilterColumns = field.Columns
+			}
+		}
+	}
+	return f
+}
+
+// FilterFromJSON unmarshals JSON sent by a client (see GetFilterFromJSON) and merges it into the schema by filter names.
+// Columns sent by the client are ignored, including the ones in Predicate leaves, which are matched with fields by names as well
+// (class leaves with FilterClass or FilterClassOR fields, string class leaves with FilterStringClass or FilterStringClassOR fields). Filters which are not declared in the schema, and relations and values which are not allowed,
+// are rejected and returned as FilterErrors, the returned Filter contains only the accepted filters (Predicate is rejected entirely).
+func (s FilterSchema) FilterFromJSON(JSON []byte, dateConvFunc func(string) int64, dateTimeConvFunc func(string) int64) (Filter, error) {
+	var client, f Filter
+	if err := json.Unmarshal(JSON, &client); err != nil {
+		return f, fmt.Errorf("%s: %w", currentFunction(), err)
+	}
+	client.convertJSONValues(dateConvFunc, dateTimeConvFunc)
+	var errs FilterErrors
+
+	for _, cf := range client.ClassFilter {
+		field, ok := s.field(cf.Name, FilterClass)
+		if !ok {
+			errs = append(errs, FilterError{Name: cf.Name, Err: ErrUnknownFilter})
+			continue
+		}
+		f.ClassFilter = append(f.ClassFilter, ClassFilter{Name: field.Name, Selector: field.Selector, InJSON: field.InJSON, Column: field.Column, List: cf.List})
+	}
+
+	var names []string
+	lists := map[string][]int{}
+	for _, cf := range client.ClassFilterOR {
+		if _, ok := lists[cf.Name]; !ok {
+			names = append(names, cf.Name)
+		}
+		for _, v := range cf.List {
+			if !containsInt(lists[cf.Name], v) {
+				lists[cf.Name] = append(lists[cf.Name], v)
+			}
+		}
+	}
+	for _, name := range names {
+		var found bool
+		for _, field := range s {
+			if field.Name == name && field.Kind == FilterClassOR {
+				found = true
+				f.ClassFilterOR = append(f.ClassFilterOR, ClassFilter{Name: field.Name, Selector: field.Selector, InJSON: field.InJSON, Column: field.Column, List: lists[name]})
+			}
+		}
+		if !found {
+			errs = append(errs, FilterError{Name: name, Err: ErrUnknownFilter})
+		}
+	}
+
+	for _, cf := range client.StringClassFilter {
+		field, ok := s.field(cf.Name, FilterStringClass)
+		if !ok {
+			errs = append(errs, FilterError{Name: cf.Name, Err: ErrUnknownFilter})
+			continue
+		}
+		f.StringClassFilter = append(f.StringClassFilter, StringClassFilter{Name: field.Name, Selector: field.Selector, InJSON: field.InJSON, CaseInsensitive: field.CaseInsensitive, Column: field.Column, List: cf.List})
+	}
+
+	names = nil
+	stringLists := map[string][]string{}
+	for _, cf := range client.StringClassFilterOR {
+		if _, ok := stringLists[cf.Name]; !ok {
+			names = append(names, cf.Name)
+		}
+		for _, v := range cf.List {
+			if !containsString(stringLists[cf.Name], v) {
+				stringLists[cf.Name] = append(stringLists[cf.Name], v)
+			}
+		}
+	}
+	for _, name := range names {
+		var found bool
+		for _, field := range s {
+			if field.Name == name && field.Kind == FilterStringClassOR {
+				found = true
+				f.StringClassFilterOR = append(f.StringClassFilterOR, StringClassFilter{Name: field.Name, Selector: field.Selector, InJSON: field.InJSON, CaseInsensitive: field.CaseInsensitive, Column: field.Column, List: stringLists[name]})
+			}
+		}
+		if !found {
+			errs = append(errs, FilterError{Name: name, Err: ErrUnknownFilter})
+		}
+	}
+
+	for _, nf := range client.NullFilter {
+		field, ok := s.field(nf.Name, FilterNull)
+		if !ok {
+			errs = append(errs, FilterError{Name: nf.Name, Err: ErrUnknownFilter})
+			continue
+		}
+		f.NullFilter = append(f.NullFilter, NullFilter{Name: field.Name, InJSON: field.InJSON, Column: field.Column, Null: nf.Null})
+	}
+
+	for _, bf := range client.BoolFilter {
+		field, ok := s.field(bf.Name, FilterBool)
+		if !ok {
+			errs = append(errs, FilterError{Name: bf.Name, Err: ErrUnknownFilter})
+			continue
+		}
+		f.BoolFilter = append(f.BoolFilter, BoolFilter{Name: field.Name, Column: field.Column, Value: bf.Value})
+	}
+
+	for _, df := range client.DateFilter {
+		field, ok := s.field(df.Name, FilterDate)
+		if !ok {
+			errs = append(errs, FilterError{Name: df.Name, Err: ErrUnknownFilter})
+			continue
+		}
+		f.DateFilter = append(f.DateFilter, DateFilter{Name: field.Name, Column: field.Column, Relation: df.Relation, Dates: df.Dates, DatesStr: df.DatesStr})
+	}
+
+	for _, sf := range client.SumFilter {
+		field, ok := s.field(sf.Name, FilterSum)
+		if !ok {
+			errs = append(errs, FilterError{Name: sf.Name, Err: ErrUnknownFilter})
+			continue
+		}
+		f.SumFilter = append(f.SumFilter, SumFilter{Name: field.Name, Column: field.Column, CurrencyColumn: field.CurrencyColumn, CurrencyCode: sf.CurrencyCode, Relation: sf.Relation, Sums: sf.Sums, SumsStr: sf.SumsStr})
+	}
+
+	if client.TextFilter != "" {
+		name := client.TextFilterName
+		if name == "" {
+			name = s.Filter().TextFilterName
+		}
+		if field, ok := s.field(name, FilterText); ok {
+			f.TextFilterName, f.TextFilter, f.TextFilterColumns = field.Name, client.TextFilter, field.Columns
+		} else {
+			errs = append(errs, FilterError{Name: name, Err: ErrUnknownFilter})
+		}
+	}
+
+	if client.Predicate != nil {
+		if perrs := s.mergePredicate(client.Predicate); len(perrs) > 0 {
+			errs = append(errs, perrs...)
+		} else {
+			f.Predicate = client.Predicate
+		}
+	}
+
+	errs = append(errs, s.check(&f)...)
+	if len(errs) > 0 {
+		return f, errs
+	}
+	return f, nil
+}
+
+// FilterFromForm fills the filters of the schema from HTML form the same way as GetFilterFromForm does.
+// Form fields which are not declared in the schema are ignored, as a form usually has other fields. Relations and values which are not allowed
+// are rejected and returned as FilterErrors, the returned Filter contains only the accepted filters.
+func (s FilterSchema) FilterFromForm(r *http.Request, dateConvFunc func(string) int64, dateTimeConvFunc func(string) int64, keywords map[string]int) (Filter, error) {
+	f := s.Filter()
+	f.GetFilterFromForm(r, dateConvFunc, dateTimeConvFunc, keywords)
+	if errs := s.check(&f); len(errs) > 0 {
+		return f, errs
+	}
+	return f, nil
+}
+
+// field returns the first field with the name and kind.
+func (s FilterSchema) field(name string, kind FilterKind) (FilterField, bool) {
+	for _, field := range s {
+		if field.Name == name && field.Kind == kind {
+			return field, true
+		}
+	}
+	return FilterField{}, false
+}
+
+// check removes from the filter class, date and sum filters with relations or values not allowed by the schema, and returns errors for them.
+func (s FilterSchema) check(f *Filter) (errs FilterErrors) {
+	rejected := map[string]bool{}
+	checkList := func(cfs []ClassFilter, kind FilterKind) (accepted []ClassFilter) {
+		for _, cf := range cfs {
+			field, _ := s.field(cf.Name, kind)
+			if err := field.checkClass(cf); err != nil {
+				if !rejected[cf.Name] {
+					rejected[cf.Name] = true
+					errs = append(errs, FilterError{Name: cf.Name, Err: err})
+				}
+				continue
+			}
+			accepted = append(accepted, cf)
+		}
+		return accepted
+	}
+	f.ClassFilter = checkList(f.ClassFilter, FilterClass)
+	f.ClassFilterOR = checkList(f.ClassFilterOR, FilterClassOR)
+
+	var dates []DateFilter
+	for _, df := range f.DateFilter {
+		field, _ := s.field(df.Name, FilterDate)
+		if err := field.checkDate(df); err != nil {
+			errs = append(errs, FilterError{Name: df.Name, Err: err})
+			continue
+		}
+		dates = append(dates, df)
+	}
+	f.DateFilter = dates
+
+	var sums []SumFilter
+	for _, sf := range f.SumFilter {
+		field, _ := s.field(sf.Name, FilterSum)
+		if err := field.checkSum(sf); err != nil {
+			errs = append(errs, FilterError{Name: sf.Name, Err: err})
+			continue
+		}
+		sums = append(sums, sf)
+	}
+	f.SumFilter = sums
+	return errs
+}
+
+// mergePredicate sets columns of the predicate leaves from the schema fields with the same names and checks their values.
+func (s FilterSchema) mergePredicate(p *Predicate) (errs FilterErrors) {
+	p.walk(func(leaf *Predicate) {
+		var name string
+		var err error
+		switch {
+		case leaf.Class != nil:
+			name = leaf.Class.Name
+			field, ok := s.field(name, FilterClass)
+			if !ok {
+				field, ok = s.field(name, FilterClassOR)
+			}
+			if !ok {
+				err = ErrUnknownFilter
+				break
+			}
+			leaf.Class.Selector, leaf.Class.InJSON, leaf.Class.Column = field.Selector, field.InJSON, field.Column
+			err = field.checkClass(*leaf.Class)
+		case leaf.StringClass != nil:
+			name = leaf.StringClass.Name
+			field, ok := s.field(name, FilterStringClass)
+			if !ok {
+				field, ok = s.field(name, FilterStringClassOR)
+			}
+			if !ok {
+				err = ErrUnknownFilter
+				break
+			}
+			leaf.StringClass.Selector, leaf.StringClass.InJSON, leaf.StringClass.CaseInsensitive, leaf.StringClass.Column = field.Selector, field.InJSON, field.CaseInsensitive, field.Column
+		case leaf.Null != nil:
+			name = leaf.Null.Name
+			field, ok := s.field(name, FilterNull)
+			if !ok {
+				err = ErrUnknownFilter
+				break
+			}
+			leaf.Null.InJSON, leaf.Null.Column = field.InJSON, field.Column
+		case leaf.Bool != nil:
+			name = leaf.Bool.Name
+			field, ok := s.field(name, FilterBool)
+			if !ok {
+				err = ErrUnknownFilter
+				break
+			}
+			leaf.Bool.Column = field.Column
+		case leaf.Date != nil:
+			name = leaf.Date.Name
+			field, ok := s.field(name, FilterDate)
+			if !ok {
+				err = ErrUnknownFilter
+				break
+			}
+			leaf.Date.Column = field.Column
+			err = field.checkDate(*leaf.Date)
+		case leaf.Sum != nil:
+			name = leaf.Sum.Name
+			field, ok := s.field(name, FilterSum)
+			if !ok {
+				err = ErrUnknownFilter
+				break
+			}
+			leaf.Sum.Column, leaf.Sum.CurrencyColumn = field.Column, field.CurrencyColumn
+			err = field.checkSum(*leaf.Sum)
+		case leaf.Text != nil:
+			name = leaf.Text.Name
+			field, ok := s.field(name, FilterText)
+			if !ok {
+				err = ErrUnknownFilter
+				break
+			}
+			leaf.Text.Columns = field.Columns
+		}
+		if err != nil {
+			errs = append(errs, FilterError{Name: name, Err: err})
+		}
+	})
+	if len(errs) > 0 {
+		return errs
+	}
+	// the predicate is validated after the merge, as text columns come from the schema
+	if err := p.validate(); err != nil {
+		return FilterErrors{{Name: "Predicate", Err: err}}
+	}
+	return nil
+}
+
+// checkClass returns an error if any value of the class filter is out of bounds.
+func (field FilterField) checkClass(cf ClassFilter) error {
+	for _, v := range cf.List {
+		if !field.inBounds(int64(v)) {
+			return ErrFilterValue
+		}
+	}
+	return nil
+}
+
+// checkDate returns an error if the date filter has more than two dates, relation which is not allowed, or dates out of bounds.
+func (field FilterField) checkDate(df DateFilter) error {
+	if len(df.Dates) > 2 {
+		return ErrFilterValue
+	}
+	if len(df.Dates) == 1 && !field.allowsRelation(df.Relation) {
+		return ErrFilterRelation
+	}
+	for _, v := range df.Dates {
+		if !field.inBounds(v) {
+			return ErrFilterValue
+		}
+	}
+	return nil
+}
+
+// checkSum returns an error if the sum filter has more than two sums, relation which is not allowed, or sums out of bounds.
+func (field FilterField) checkSum(sf SumFilter) error {
+	if len(sf.Sums) > 2 {
+		return ErrFilterValue
+	}
+	if len(sf.Sums) == 1 && !field.allowsRelation(sf.Relation) {
+		return ErrFilterRelation
+	}
+	for _, v := range sf.Sums {
+		if !field.inBounds(int64(v)) {
+			return ErrFilterValue
+		}
+	}
+	return nil
+}
+
+// allowsRelation reports whether the relation is one of Relations, relations with the same meaning (e.g. "gt" and ">") are equal.
+func (field FilterField) allowsRelation(relation string) bool {
+	if len(field.Relations) == 0 {
+		return true
+	}
+	for _, r := range field.Relations {
+		if getRelationFromString(r) == getRelationFromString(relation) {
+			return true
+		}
+	}
+	return false
+}
+
+// inBounds reports whether the value is between Min and Max inclusive, or Max is not greater than Min.
+func (field FilterField) inBounds(v int64) bool {
+	return field.Max <= field.Min || (v >= field.Min && v <= field.Max)
+}
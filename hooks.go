@@ -0,0 +1,76 @@
+package sqla
+
+import (
+	"context"
+	"sync"
+)
+
+// HookData is passed to hooks, see Hooks.
+// Args contains values to insert or update, Before hooks may change them, e.g. append Modified column. It is nil for delete.
+// Keys contains the key of updated object, or values of Column to delete objects by (nil for PurgeDeleted, then Column is soft delete column),
+// or the key of inserted object (in AfterInsert, if it is known).
+// RowsAffected is the number of affected rows, it is set only for After hooks.
+type HookData struct {
+	Table        string
+	Args         AnyTslice
+	Column       string
+	Keys         []interface{}
+	RowsAffected int
+}
+
+// Hook is a function called before or after a change of data. If it returns an error the operation is aborted and the error is returned by the function which called the hook.
+// Note that an error of After hook rolls the change back only if it is made in a transaction (e.g. db is *sql.Tx, or audit is enabled for the table).
+type Hook func(ctx context.Context, data *HookData) error
+
+// Hooks contains callbacks for InsertObject, UpdateObject and delete functions (including PurgeDeleted) with all their variants.
+// Any of them may be nil. Other functions which change data (e.g. InsertObjects, UpsertObject, SetToNull) do not call hooks.
+type Hooks struct {
+	BeforeInsert Hook
+	AfterInsert  Hook
+	BeforeUpdate Hook
+	AfterUpdate  Hook
+	BeforeDelete Hook
+	AfterDelete  Hook
+}
+
+var (
+	hooksMu sync.RWMutex
+	hooks   = map[string][]Hooks{}
+)
+
+// RegisterHooks adds hooks for the table, or global hooks for all tables if table is empty string.
+// Hooks are called in the order of registration, global hooks are called before the hooks of a table.
+func RegisterHooks(table string, h Hooks) {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	hooks[table] = append(hooks[table], h)
+}
+
+// ResetHooks removes all hooks registered for the table, or global hooks if table is empty string.
+func ResetHooks(table string) {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	delete(hooks, table)
+}
+
+// runHooks calls hooks chosen by pick for the table, and stops on the first error.
+func runHooks(ctx context.Context, pick func(h Hooks) Hook, data *HookData) error {
+	hooksMu.RLock()
+	list := append(append([]Hooks{}, hooks[""]...), hooks[data.Table]...)
+	hooksMu.RUnlock()
+	for _, h := range list {
+		if hook := pick(h); hook != nil {
+			if err := hook(ctx, data); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func beforeInsert(h Hooks) Hook { return h.BeforeInsert }
+func afterInsert(h Hooks) Hook  { return h.AfterInsert }
+func beforeUpdate(h Hooks) Hook { return h.BeforeUpdate }
+func afterUpdate(h Hooks) Hook  { return h.AfterUpdate }
+func beforeDelete(h Hooks) Hook { return h.BeforeDelete }
+func afterDelete(h Hooks) Hook  { return h.AfterDelete }
@@ -0,0 +1,83 @@
+package sqla
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+func TestHooks(t *testing.T) {
+	var DBType byte
+	DBType = SQLITE
+	var db *sql.DB
+	db = OpenSQLConnection(DBType, "file:hookstest?mode=memory&cache=shared")
+	defer db.Close()
+	db.Exec("CREATE TABLE notes (ID INTEGER PRIMARY KEY, Body TEXT, Modified INTEGER);")
+	defer ResetHooks("notes")
+	defer ResetHooks("")
+
+	errEmptyBody := errors.New("empty body")
+	var calls []string
+	RegisterHooks("", Hooks{
+		AfterDelete: func(ctx context.Context, data *HookData) error {
+			calls = append(calls, "global after delete "+data.Table)
+			return nil
+		},
+	})
+	RegisterHooks("notes", Hooks{
+		BeforeInsert: func(ctx context.Context, data *HookData) error {
+			for _, v := range data.Args {
+				if v.c == "Body" && v.s == "" {
+					return errEmptyBody
+				}
+			}
+			data.Args = data.Args.AppendInt64("Modified", 1)
+			return nil
+		},
+		AfterInsert: func(ctx context.Context, data *HookData) error {
+			calls = append(calls, "after insert")
+			if len(data.Keys) != 1 || data.Keys[0] == int64(0) || data.RowsAffected != 1 {
+				t.Errorf("Expected:%s, received:%v, %d", "key of inserted row", data.Keys, data.RowsAffected)
+			}
+			return nil
+		},
+		BeforeUpdate: func(ctx context.Context, data *HookData) error {
+			data.Args = data.Args.AppendInt64("Modified", 2)
+			return nil
+		},
+		BeforeDelete: func(ctx context.Context, data *HookData) error {
+			calls = append(calls, "before delete")
+			return nil
+		},
+	})
+
+	var args AnyTslice
+	args = args.AppendString("Body", "")
+	if _, _, err := InsertObjectContext(context.Background(), db, DBType, "notes", args); !errors.Is(err, errEmptyBody) {
+		t.Errorf("Expected:%v, received:%v", errEmptyBody, err)
+	}
+	args = nil
+	args = args.AppendString("Body", "text")
+	ID, _ := InsertObject(db, DBType, "notes", args)
+	var modified int
+	db.QueryRow("SELECT Modified FROM notes WHERE ID = $1", ID).Scan(&modified)
+	if modified != 1 {
+		t.Errorf("Expected:%d, received:%d", 1, modified)
+	}
+	UpdateObject(db, DBType, "notes", args, ID)
+	db.QueryRow("SELECT Modified FROM notes WHERE ID = $1", ID).Scan(&modified)
+	if modified != 2 {
+		t.Errorf("Expected:%d, received:%d", 2, modified)
+	}
+	DeleteObjects(db, DBType, "notes", "ID", []int{ID})
+	expected := []string{"after insert", "before delete", "global after delete notes"}
+	if len(calls) != len(expected) {
+		t.Fatalf("Expected:%v, received:%v", expected, calls)
+	}
+	for i := range expected {
+		if calls[i] != expected[i] {
+			t.Errorf("Expected:%s, received:%s", expected[i], calls[i])
+		}
+	}
+}
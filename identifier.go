@@ -0,0 +1,189 @@
+package sqla
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalidIdentifier is returned if a table or column name contains characters other than letters (of any alphabet), digits, _, $ and # (and dots between parts of a name),
+// so it cannot be safely pasted into SQL statement.
+var ErrInvalidIdentifier = errors.New("invalid identifier")
+
+// ErrUnknownColumn is returned if a column is not present in TableMeta.Columns of its table.
+var ErrUnknownColumn = errors.New("unknown column")
+
+var identifierRegExp = regexp.MustCompile(`^[\p{L}_][\p{L}\p{N}_$#]*(\.[\p{L}_][\p{L}\p{N}_$#]*)*$`)
+
+// ValidIdentifier reports whether name is a plain or dotted (e.g. table.column) identifier which is safe to paste into SQL statement without quoting.
+// Use Dialect.QuoteIdentifier for names which are not.
+func ValidIdentifier(name string) bool {
+	return identifierRegExp.MatchString(name)
+}
+
+// QuoteIdentifier quotes a table or column name for the database, e.g. "name", [name] or `name`. Each part of dotted name is quoted separately.
+// Note that quoted names are case-sensitive in some databases.
+func QuoteIdentifier(DBType byte, name string) string {
+	return GetDialect(DBType).QuoteIdentifier(name)
+}
+
+// ValidateColumns checks that the table name and every column are valid identifiers (see ValidIdentifier), and that columns are allowed for the table by TableMeta.Columns.
+// Columns qualified with other table name (e.g. users.Name) are checked against the metadata of that table.
+// If the table has an allow-list, other tables (and aliases) should be registered with their own allow-lists to be used this way.
+func ValidateColumns(table string, columns ...string) error {
+	meta := GetTableMeta(table)
+	if err := meta.checkTable(); err != nil {
+		return err
+	}
+	for _, col := range columns {
+		if err := meta.checkColumn(col); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// quote returns the name quoted for the database if TableMeta.QuoteNames is set, otherwise the name as it is.
+func (m TableMeta) quote(DBType byte, name string) string {
+	if !m.QuoteNames || name == "" {
+		return name
+	}
+	return QuoteIdentifier(DBType, name)
+}
+
+// quoteAll returns names quoted with quote.
+func (m TableMeta) quoteAll(DBType byte, names []string) []string {
+	if !m.QuoteNames {
+		return names
+	}
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = m.quote(DBType, name)
+	}
+	return quoted
+}
+
+// checkTable returns an error if the table name is not a valid identifier.
+func (m TableMeta) checkTable() error {
+	if !ValidIdentifier(m.Name) {
+		return fmt.Errorf("%w: %q", ErrInvalidIdentifier, m.Name)
+	}
+	return nil
+}
+
+// checkColumn returns an error if the column is not a valid identifier or is not present in the allow-list of its table.
+func (m TableMeta) checkColumn(column string) error {
+	if !ValidIdentifier(column) {
+		return fmt.Errorf("%w: %q", ErrInvalidIdentifier, column)
+	}
+	if i := strings.LastIndex(column, "."); i >= 0 {
+		if qualifier := column[:i]; !strings.EqualFold(qualifier, m.Name) {
+			other := GetTableMeta(qualifier)
+			if len(m.Columns) > 0 && len(other.Columns) == 0 {
+				// the allow-list of the table should not be bypassed with a table (or alias) which has no allow-list
+				return fmt.Errorf("%w: %s", ErrUnknownColumn, column)
+			}
+			return other.checkColumn(column[i+1:])
+		}
+		column = column[i+1:]
+	}
+	if len(m.Columns) == 0 {
+		return nil
+	}
+	for _, col := range m.Columns {
+		if strings.EqualFold(col, column) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s.%s", ErrUnknownColumn, m.Name, column)
+}
+
+// checkArgs checks the table name and the columns of values to be written into the table.
+func (m TableMeta) checkArgs(iargs []anyT) error {
+	if err := m.checkTable(); err != nil {
+		return err
+	}
+	for _, v := range iargs {
+		if err := m.checkColumn(v.c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate checks all the columns of the filter with ValidateColumns, empty column names are skipped, and the structure of Predicate.
+// SelectBuilder and ConstructSELECTquery do it before building statements, but you may call it to reject a filter received from a client earlier.
+func (f *Filter) Validate(table string) error {
+	if f.Predicate != nil {
+		if err := f.Predicate.validate(); err != nil {
+			return err
+		}
+	}
+	var columns []string
+	f.mapColumns(func(col string) string {
+		columns = append(columns, col)
+		return col
+	})
+	meta := GetTableMeta(table)
+	for _, col := range columns {
+		if err := meta.checkColumn(col); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mapColumns returns a copy of the filter with every column name (including columns of Predicate) replaced by fn, empty names are not passed to fn.
+func (f Filter) mapColumns(fn func(col string) string) Filter {
+	name := func(col string) string {
+		if col == "" {
+			return col
+		}
+		return fn(col)
+	}
+	classFilters := func(list []ClassFilter) []ClassFilter {
+		list = append([]ClassFilter(nil), list...)
+		for i := range list {
+			list[i].Column = name(list[i].Column)
+		}
+		return list
+	}
+	stringClassFilters := func(list []StringClassFilter) []StringClassFilter {
+		list = append([]StringClassFilter(nil), list...)
+		for i := range list {
+			list[i].Column = name(list[i].Column)
+		}
+		return list
+	}
+	f.ClassFilter = classFilters(f.ClassFilter)
+	f.ClassFilterOR = classFilters(f.ClassFilterOR)
+	f.StringClassFilter = stringClassFilters(f.StringClassFilter)
+	f.StringClassFilterOR = stringClassFilters(f.StringClassFilterOR)
+	f.NullFilter = append([]NullFilter(nil), f.NullFilter...)
+	for i := range f.NullFilter {
+		f.NullFilter[i].Column = name(f.NullFilter[i].Column)
+	}
+	f.BoolFilter = append([]BoolFilter(nil), f.BoolFilter...)
+	for i := range f.BoolFilter {
+		f.BoolFilter[i].Column = name(f.BoolFilter[i].Column)
+	}
+	f.DateFilter = append([]DateFilter(nil), f.DateFilter...)
+	for i := range f.DateFilter {
+		f.DateFilter[i].Column = name(f.DateFilter[i].Column)
+	}
+	f.SumFilter = append([]SumFilter(nil), f.SumFilter...)
+	for i := range f.SumFilter {
+		f.SumFilter[i].Column = name(f.SumFilter[i].Column)
+		f.SumFilter[i].CurrencyColumn = name(f.SumFilter[i].CurrencyColumn)
+	}
+	f.TextFilterColumns = append([]string(nil), f.TextFilterColumns...)
+	for i := range f.TextFilterColumns {
+		f.TextFilterColumns[i] = name(f.TextFilterColumns[i])
+	}
+	if f.Predicate != nil {
+		p := f.Predicate.mapColumns(name)
+		f.Predicate = &p
+	}
+	return f
+}
@@ -0,0 +1,116 @@
+package sqla
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+func TestValidateColumns(t *testing.T) {
+	var DBType byte
+	DBType = SQLITE
+	var db *sql.DB
+	db = OpenSQLConnection(DBType, "file:identifiertest?mode=memory&cache=shared")
+	defer db.Close()
+	db.Exec("CREATE TABLE receipts (ID INTEGER PRIMARY KEY, Amount INTEGER, Customer INTEGER);")
+	RegisterTable(TableMeta{Name: "receipts", Columns: []string{"ID", "Amount", "Customer"}})
+	RegisterTable(TableMeta{Name: "customers", Columns: []string{"ID", "Name"}})
+
+	var tests = []struct {
+		columns  []string
+		expected error
+	}{
+		{[]string{"ID", "amount", "receipts.Customer", "customers.Name"}, nil},
+		{[]string{"Amount", "Total"}, ErrUnknownColumn},
+		{[]string{"ID; DROP TABLE receipts"}, ErrInvalidIdentifier},
+		{[]string{"receipts.Total"}, ErrUnknownColumn},
+		{[]string{"customers.Secret"}, ErrUnknownColumn},
+		{[]string{"other.Secret"}, ErrUnknownColumn},
+		{[]string{""}, ErrInvalidIdentifier},
+	}
+	for _, tt := range tests {
+		if err := ValidateColumns("receipts", tt.columns...); !errors.Is(err, tt.expected) {
+			t.Errorf("Expected:%v, received:%v", tt.expected, err)
+		}
+	}
+
+	F := Filter{ClassFilter: []ClassFilter{{Name: "customer", Column: "Customer", List: []int{1}}}}
+	if _, _, err := NewSelectBuilder(DBType).From("receipts").Where(F).OrderBy("Amount, ID", 1).Build(); err != nil {
+		t.Errorf("Expected:%v, received:%v", nil, err)
+	}
+	F.ClassFilter[0].Column = "1=1 OR Customer"
+	if _, _, err := NewSelectBuilder(DBType).From("receipts").Where(F).BuildCount(); !errors.Is(err, ErrInvalidIdentifier) {
+		t.Errorf("Expected:%v, received:%v", ErrInvalidIdentifier, err)
+	}
+	if _, _, err := NewSelectBuilder(DBType).From("receipts").OrderBy("Total", 1).Build(); !errors.Is(err, ErrUnknownColumn) {
+		t.Errorf("Expected:%v, received:%v", ErrUnknownColumn, err)
+	}
+
+	var args AnyTslice
+	args = args.AppendInt("Amount", 100)
+	ID, rowsaff := InsertObject(db, DBType, "receipts", args)
+	if rowsaff != 1 {
+		t.Errorf("Expected:%d, received:%d", 1, rowsaff)
+	}
+	args = args.AppendInt("Discount", 10)
+	if _, _, err := InsertObjectContext(context.Background(), db, DBType, "receipts", args); !errors.Is(err, ErrUnknownColumn) {
+		t.Errorf("Expected:%v, received:%v", ErrUnknownColumn, err)
+	}
+	if _, err := UpdateObjectContext(context.Background(), db, DBType, "receipts", args, ID); !errors.Is(err, ErrUnknownColumn) {
+		t.Errorf("Expected:%v, received:%v", ErrUnknownColumn, err)
+	}
+	if _, _, err := InsertObjectsContext(context.Background(), db, DBType, "receipts", []AnyTslice{args}, false); !errors.Is(err, ErrUnknownColumn) {
+		t.Errorf("Expected:%v, received:%v", ErrUnknownColumn, err)
+	}
+}
+
+func TestValidateIdentifiers(t *testing.T) {
+	var DBType byte
+	DBType = SQLITE
+	var db *sql.DB
+	db = OpenSQLConnection(DBType, "file:identifierstest?mode=memory&cache=shared")
+	defer db.Close()
+	RegisterTable(TableMeta{Name: "receipts", Columns: []string{"ID", "Amount", "Customer"}})
+	if _, err := DeleteObjectsContext(context.Background(), db, DBType, "receipts", "ID = 1 OR ID", []int{1}); !errors.Is(err, ErrInvalidIdentifier) {
+		t.Errorf("Expected:%v, received:%v", ErrInvalidIdentifier, err)
+	}
+	if _, err := SetToNullContext(context.Background(), db, DBType, "receipts; DROP TABLE receipts", "Amount", []int{1}); !errors.Is(err, ErrInvalidIdentifier) {
+		t.Errorf("Expected:%v, received:%v", ErrInvalidIdentifier, err)
+	}
+	if _, err := UpdateMultipleWithOneIntContext(context.Background(), db, DBType, "receipts", "Amount", 1, "Changed", 0, []int{1}); !errors.Is(err, ErrUnknownColumn) {
+		t.Errorf("Expected:%v, received:%v", ErrUnknownColumn, err)
+	}
+	if VerifyRemovalPermissions(db, DBType, "receipts; DROP TABLE receipts", 1, false, true, []int{1}) {
+		t.Errorf("Expected:%v, received:%v", false, true)
+	}
+	if sq, _, _, _ := ConstructSELECTquery(DBType, "receipts r", "*", "*", "", Filter{}, "r.Amount", 1, 0, 0, false, Seek{}); sq != "SELECT * FROM receipts r  ORDER BY r.Amount COLLATE NOCASE ASC  LIMIT $1 OFFSET $2" {
+		t.Errorf("Expected:%s, received:%s", "SELECT * FROM receipts r  ORDER BY r.Amount COLLATE NOCASE ASC  LIMIT $1 OFFSET $2", sq)
+	}
+	if !ValidIdentifier("Счета.Сумма_1") || ValidIdentifier("1Сумма") {
+		t.Errorf("Expected:%s, received:%v", "non-ASCII letters accepted", ValidIdentifier("Счета.Сумма_1"))
+	}
+
+	RegisterTable(TableMeta{Name: "orders", QuoteNames: true})
+	F := Filter{ClassFilter: []ClassFilter{{Column: "Order Status", List: []int{1}}}}
+	if _, _, err := NewSelectBuilder(POSTGRESQL).From("orders").Where(F).Build(); !errors.Is(err, ErrInvalidIdentifier) {
+		t.Errorf("Expected:%v, received:%v", ErrInvalidIdentifier, err)
+	}
+	F.ClassFilter[0].Column = "Status"
+	sq, _, err := NewSelectBuilder(POSTGRESQL).From("orders").Where(F).OrderBy("Created", 0).Build()
+	expected := `SELECT * FROM orders  WHERE "Status" IN ($1) ORDER BY "Created" DESC `
+	if err != nil || sq != expected || F.ClassFilter[0].Column != "Status" {
+		t.Errorf("Expected:%s, received:%s, %v", expected, sq, err)
+	}
+	db.Exec("CREATE TABLE orders (ID INTEGER PRIMARY KEY, Status INTEGER);")
+	var args AnyTslice
+	args = args.AppendInt("Status", 1)
+	if ID, rowsaff := InsertObject(db, DBType, "orders", args); rowsaff != 1 || UpdateObject(db, DBType, "orders", args, ID) != 1 {
+		t.Errorf("Expected:%d, received:%d", 1, rowsaff)
+	}
+	sq, _, err = NewSelectBuilder(MSSQL).From("orders").Where(F).Build()
+	expected = `SELECT * FROM orders  WHERE [Status] IN (@p1) `
+	if err != nil || sq != expected {
+		t.Errorf("Expected:%s, received:%s, %v", expected, sq, err)
+	}
+}
@@ -1,13 +1,92 @@
 package sqla
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
 	"log"
+	"reflect"
+	"strings"
 )
 
 // InsertObject creates an SQL statement and executes it to insert an object into the specified table.
-// It returns the ID of created record and the number of affected rows. ID column should be named 'ID'.
-func InsertObject(db *sql.DB, DBType byte, table string, iargs []anyT) (lastid int, rowsaff int) {
+// It returns the ID of created record and the number of affected rows. ID column should be named 'ID' unless other key is registered with RegisterTable.
+// For tables with composite or non-integer keys the returned ID is 0.
+// Any error is only logged, use InsertObjectContext to receive it.
+func InsertObject(db Executor, DBType byte, table string, iargs []anyT) (lastid int, rowsaff int) {
+	lastid, rowsaff, err := InsertObjectContext(context.Background(), db, DBType, table, iargs)
+	if err != nil {
+		log.Println(err)
+	}
+	return lastid, rowsaff
+}
+
+// InsertObjectContext does the same as InsertObject, but it takes a context to cancel the query or to set a timeout for it,
+// and returns an error instead of logging it. The returned error wraps the one returned by the database driver.
+func InsertObjectContext(ctx context.Context, db Executor, DBType byte, table string, iargs []anyT) (lastid int, rowsaff int, err error) {
+	meta := GetTableMeta(table)
+	if len(meta.Key) != 1 || meta.KeyType != KeyInt64 {
+		rowsaff, err = InsertObjectKeyContext(ctx, db, DBType, table, iargs, nil)
+		return 0, rowsaff, err
+	}
+	var ID sql.NullInt64
+	rowsaff, err = InsertObjectKeyContext(ctx, db, DBType, table, iargs, &ID)
+	return int(ID.Int64), rowsaff, err
+}
+
+// InsertObjectKey does the same as InsertObject, but the key of created record is stored into the value pointed by key, like sql.Row.Scan does.
+// It allows to get keys of any type, e.g. key may be *string, *int64 or a pointer to a UUID type implementing sql.Scanner, see also RegisterTable.
+// key may be nil if the key is not needed, or if the table has composite key.
+//
+// For dialects which take the key from sql.Result.LastInsertId (SQLite, MySQL) non-integer keys can not be returned,
+// although if the key column is present in iargs (e.g. UUID generated by your app) its value is stored into key.
+// Any error is only logged, use InsertObjectKeyContext to receive it.
+func InsertObjectKey(db Executor, DBType byte, table string, iargs []anyT, key interface{}) (rowsaff int) {
+	rowsaff, err := InsertObjectKeyContext(context.Background(), db, DBType, table, iargs, key)
+	if err != nil {
+		log.Println(err)
+	}
+	return rowsaff
+}
+
+// InsertObjectKeyContext does the same as InsertObjectKey, but it takes a context and returns an error instead of logging it.
+func InsertObjectKeyContext(ctx context.Context, db Executor, DBType byte, table string, iargs []anyT, key interface{}) (rowsaff int, err error) {
+
+	meta := GetTableMeta(table)
+	if ok, err := auditInTx(ctx, db, meta, func(tx *sql.Tx) (err error) {
+		rowsaff, err = InsertObjectKeyContext(ctx, tx, DBType, table, iargs, key)
+		return err
+	}); ok {
+		return rowsaff, err
+	}
+	fname := currentFunction()
+	if meta.Audit {
+		defer func() {
+			if err != nil || rowsaff == 0 {
+				return
+			}
+			if err = writeAudit(ctx, db, DBType, table, AuditInsert, auditKey(insertedKey(meta, iargs, key)), nil, anyTMap(iargs)); err != nil {
+				err = wrapError(fname, err)
+			}
+		}()
+	}
+	data := &HookData{Table: table, Args: iargs}
+	if err = runHooks(ctx, beforeInsert, data); err != nil {
+		return 0, fmt.Errorf("%s: %w", fname, err)
+	}
+	iargs = data.Args
+	if err = meta.checkArgs(iargs); err != nil {
+		return 0, fmt.Errorf("%s: %w", fname, err)
+	}
+	defer func() {
+		if err != nil {
+			return
+		}
+		data.Keys, data.RowsAffected = insertedKey(meta, iargs, key), rowsaff
+		if err = runHooks(ctx, afterInsert, data); err != nil {
+			err = fmt.Errorf("%s: %w", fname, err)
+		}
+	}()
 
 	const (
 		I = 0
@@ -27,7 +106,7 @@ func InsertObject(db *sql.DB, DBType byte, table string, iargs []anyT) (lastid i
 			columns += ", "
 			values += ", "
 		}
-		columns += iargs[j].c
+		columns += meta.quote(DBType, iargs[j].c)
 		values += MakeParam(DBType, counter)
 		switch iargs[j].t {
 		case I:
@@ -43,91 +122,316 @@ func InsertObject(db *sql.DB, DBType byte, table string, iargs []anyT) (lastid i
 		}
 	}
 
-	if DBType == SQLITE || DBType == MYSQL {
+	keyColumn, err := meta.keyColumn()
+	if key == nil || err != nil {
+		if key != nil {
+			return 0, wrapError(currentFunction(), err)
+		}
 		sq := "INSERT INTO " + table + " (" + columns + ") VALUES (" + values + ")"
+		return execRowsAffected(ctx, db, currentFunction(), sq, args)
+	}
+	keyValue := reflect.ValueOf(key)
+	if keyValue.Kind() != reflect.Ptr || keyValue.IsNil() {
+		return 0, fmt.Errorf("%s: key should be a non-nil pointer", currentFunction())
+	}
+
+	sq, strategy := GetDialect(DBType).InsertReturningID(table, columns, values, keyColumn, MakeParam(DBType, counter+1))
+
+	switch strategy {
+	case LastInsertID:
 		if DEBUG {
 			log.Println(sq, args)
 		}
-		res, err := db.Exec(sq, args...)
-		if err != nil {
-			log.Println(currentFunction()+":", err)
-			return
-		}
-		li, err := res.LastInsertId()
+		res, err := db.ExecContext(ctx, sq, args...)
 		if err != nil {
-			log.Println(currentFunction()+":", err)
+			return 0, wrapError(currentFunction(), err)
 		}
 		ra, err := res.RowsAffected()
 		if err != nil {
-			log.Println(currentFunction()+":", err)
+			return 0, wrapError(currentFunction(), err)
 		}
-		lastid = int(li)
 		rowsaff = int(ra)
-	}
+		for j := range iargs {
+			if iargs[j].c == keyColumn {
+				if err = assignValue(keyValue.Elem(), args[j], false); err != nil {
+					return rowsaff, fmt.Errorf("%s: %w", currentFunction(), err)
+				}
+				return rowsaff, nil
+			}
+		}
+		if meta.KeyType != KeyInt64 {
+			return rowsaff, fmt.Errorf("%s: database does not return %s key of table %s, pass it in iargs", currentFunction(), keyColumn, table)
+		}
+		li, err := res.LastInsertId()
+		if err != nil {
+			return rowsaff, wrapError(currentFunction(), err)
+		}
+		if err = assignValue(keyValue.Elem(), li, false); err != nil {
+			return rowsaff, fmt.Errorf("%s: %w", currentFunction(), err)
+		}
 
-	if DBType == MSSQL {
-		sq := `DECLARE @virttable TABLE (NewID INTEGER);
-		INSERT INTO ` + table + ` (` + columns + `) OUTPUT INSERTED.ID INTO @virttable VALUES (` + values + `);
-		SELECT NewID FROM @virttable`
+	case QueryRowID:
 		if DEBUG {
 			log.Println(sq, args)
 		}
-		row := db.QueryRow(sq, args...)
-		var ra int
-		var ID sql.NullInt64
-		err := row.Scan(&ID)
+		row := db.QueryRowContext(ctx, sq, args...)
+		err := row.Scan(key)
 		if err != nil {
-			log.Println(currentFunction()+":", err)
-			return
-		}
-		if ID.Valid == true {
-			lastid = int(ID.Int64)
-			ra++
+			return 0, wrapError(currentFunction(), err)
 		}
-		rowsaff = int(ra)
-	}
+		rowsaff = 1
 
-	if DBType == ORACLE {
+	case OutParamID:
+		// integer keys are returned into *int64 as the driver expects, other keys into key itself
 		var li int64
-		counter++
-		args = append(args, &li)
-		sq := "INSERT INTO " + table + " (" + columns + ") VALUES (" + values + ") RETURNING ID INTO " + MakeParam(DBType, counter)
+		if meta.KeyType == KeyInt64 {
+			args = append(args, &li)
+		} else {
+			args = append(args, sql.Out{Dest: key})
+		}
 		if DEBUG {
 			log.Println(sq, args)
 		}
-		res, err := db.Exec(sq, args...)
+		res, err := db.ExecContext(ctx, sq, args...)
 		if err != nil {
-			log.Println(currentFunction()+":", err)
-			return
+			return 0, wrapError(currentFunction(), err)
 		}
 		ra, err := res.RowsAffected()
 		if err != nil {
-			log.Println(currentFunction()+":", err)
+			return 0, wrapError(currentFunction(), err)
 		}
-		lastid = int(li)
 		rowsaff = int(ra)
+		if meta.KeyType == KeyInt64 {
+			if err = assignValue(keyValue.Elem(), li, false); err != nil {
+				return rowsaff, fmt.Errorf("%s: %w", currentFunction(), err)
+			}
+		}
 	}
 
-	if DBType == POSTGRESQL {
-		sq := "INSERT INTO " + table + " (" + columns + ") VALUES (" + values + ") RETURNING ID"
-		if DEBUG {
-			log.Println(sq, args)
+	return rowsaff, nil
+
+}
+
+// InsertRowsBatch is the maximum number of rows inserted by one statement in InsertObjects.
+// Batches are also limited by the number of parameters allowed by the database (see Dialect.MaxParams).
+var InsertRowsBatch = 1000
+
+// InsertObjects inserts several rows into the specified table using multi-row insert statements (INSERT ALL for Oracle),
+// the rows are split into batches (see InsertRowsBatch). All rows should contain the same columns, the order of columns may differ.
+// If inTx is true and db is *sql.DB, all batches are inserted in one transaction, so either all rows are inserted or none.
+// It returns IDs of created records if the database returns them (PostgreSQL, SQLite, MSSQL, MariaDB, CockroachDB, DuckDB)
+// and the table has single integer key, otherwise ids is nil. Databases do not promise to return IDs in the order of rows, sort them if necessary.
+// If audit is enabled for the table (see TableMeta.Audit), rows are inserted one by one in a transaction and an audit record is written for each row.
+// Any error is only logged, use InsertObjectsContext to receive it.
+func InsertObjects(db Executor, DBType byte, table string, rows []AnyTslice, inTx bool) (ids []int, rowsaff int) {
+	ids, rowsaff, err := InsertObjectsContext(context.Background(), db, DBType, table, rows, inTx)
+	if err != nil {
+		log.Println(err)
+	}
+	return ids, rowsaff
+}
+
+// InsertObjectsContext does the same as InsertObjects, but it takes a context and returns an error instead of logging it.
+func InsertObjectsContext(ctx context.Context, db Executor, DBType byte, table string, rows []AnyTslice, inTx bool) (ids []int, rowsaff int, err error) {
+	if len(rows) == 0 {
+		return nil, 0, nil
+	}
+	meta := GetTableMeta(table)
+	if ok, err := auditInTx(ctx, db, meta, func(tx *sql.Tx) (err error) {
+		ids, rowsaff, err = InsertObjectsContext(ctx, tx, DBType, table, rows, false)
+		return err
+	}); ok {
+		return ids, rowsaff, err
+	}
+	columns, index, err := rowColumns(meta, rows[0])
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", currentFunction(), err)
+	}
+	values := make([][]interface{}, len(rows))
+	for i, row := range rows {
+		if values[i], err = rowArgs(index, row); err != nil {
+			return nil, 0, fmt.Errorf("%s: row %d: %w", currentFunction(), i, err)
 		}
-		row := db.QueryRow(sq, args...)
-		var ra int
-		var ID sql.NullInt64
-		err := row.Scan(&ID)
+	}
+
+	var idColumn string
+	if len(meta.Key) == 1 && meta.KeyType == KeyInt64 {
+		idColumn = meta.Key[0]
+	}
+	batch := insertBatchSize(DBType, len(columns))
+	if meta.Audit {
+		// rows are inserted one by one to match returned IDs with rows in audit records
+		batch = 1
+	}
+	columns = meta.quoteAll(DBType, columns)
+
+	insert := func(exec Executor) error {
+		ids, rowsaff = nil, 0
+		for start := 0; start < len(values); start += batch {
+			end := start + batch
+			if end > len(values) {
+				end = len(values)
+			}
+			batchIDs, ra, err := insertRows(ctx, exec, DBType, table, columns, values[start:end], idColumn)
+			rowsaff += ra
+			if err != nil {
+				return err
+			}
+			ids = append(ids, batchIDs...)
+			if meta.Audit {
+				var key interface{}
+				if len(batchIDs) == 1 {
+					key = batchIDs[0]
+				}
+				if err = writeAudit(ctx, exec, DBType, table, AuditInsert, auditKey(insertedKey(meta, rows[start], key)), nil, anyTMap(rows[start])); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	if sqldb, ok := db.(*sql.DB); ok && inTx {
+		err = WithTxContext(ctx, sqldb, nil, func(tx *sql.Tx) error { return insert(tx) })
+	} else {
+		err = insert(db)
+	}
+	if err != nil {
+		return nil, rowsaff, wrapError(currentFunction(), err)
+	}
+	return ids, rowsaff, nil
+}
+
+// insertRows executes one multi-row insert statement and returns IDs of inserted rows if the database returns them.
+func insertRows(ctx context.Context, db Executor, DBType byte, table string, columns []string, values [][]interface{}, idColumn string) (ids []int, rowsaff int, err error) {
+	var counter int
+	var args []interface{}
+	placeholders := make([]string, len(values))
+	for i := range values {
+		for j := range values[i] {
+			counter++
+			if j > 0 {
+				placeholders[i] += ", "
+			}
+			placeholders[i] += MakeParam(DBType, counter)
+		}
+		args = append(args, values[i]...)
+	}
+	sq, returnsIDs := GetDialect(DBType).InsertRows(table, strings.Join(columns, ", "), placeholders, idColumn)
+	if DEBUG {
+		log.Println(sq, args)
+	}
+	if !returnsIDs {
+		res, err := db.ExecContext(ctx, sq, args...)
 		if err != nil {
-			log.Println(currentFunction()+":", err)
-			return
+			return nil, 0, err
 		}
-		if ID.Valid == true {
-			lastid = int(ID.Int64)
-			ra++
+		ra, err := res.RowsAffected()
+		return nil, int(ra), err
+	}
+	rows, err := db.QueryContext(ctx, sq, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	var ID sql.NullInt64
+	for rows.Next() {
+		if err = rows.Scan(&ID); err != nil {
+			return nil, 0, err
 		}
-		rowsaff = int(ra)
+		ids = append(ids, int(ID.Int64))
 	}
+	return ids, len(ids), rows.Err()
+}
 
-	return lastid, rowsaff
+// insertedKey returns the key of inserted object from key destination, or from iargs if key is nil.
+func insertedKey(meta TableMeta, iargs []anyT, key interface{}) (keyValues []interface{}) {
+	if key != nil {
+		return []interface{}{keyValue(key)}
+	}
+	for _, col := range meta.Key {
+		for j := range iargs {
+			if iargs[j].c == col {
+				keyValues = append(keyValues, argValue(iargs[j]))
+			}
+		}
+	}
+	return keyValues
+}
+
+// rowColumns returns columns of the first row of multi-row insert and the map of column names to their positions.
+func rowColumns(meta TableMeta, row AnyTslice) (columns []string, index map[string]int, err error) {
+	if err = meta.checkTable(); err != nil {
+		return nil, nil, err
+	}
+	index = map[string]int{}
+	for _, v := range row {
+		if err = meta.checkColumn(v.c); err != nil {
+			return nil, nil, err
+		}
+		if _, ok := index[v.c]; ok {
+			return nil, nil, fmt.Errorf("column %s is repeated", v.c)
+		}
+		index[v.c] = len(columns)
+		columns = append(columns, v.c)
+	}
+	if len(columns) == 0 {
+		return nil, nil, fmt.Errorf("row has no columns")
+	}
+	return columns, index, nil
+}
 
+// rowArgs returns arguments for a row of multi-row insert in the order of columns, index maps column names to their positions.
+// All columns should be present in the row once.
+func rowArgs(index map[string]int, row AnyTslice) ([]interface{}, error) {
+	if len(row) != len(index) {
+		return nil, fmt.Errorf("row has %d columns instead of %d", len(row), len(index))
+	}
+	args := make([]interface{}, len(index))
+	seen := make([]bool, len(index))
+	for _, v := range row {
+		j, ok := index[v.c]
+		if !ok || seen[j] {
+			return nil, fmt.Errorf("columns do not match the first row at column %s", v.c)
+		}
+		seen[j] = true
+		args[j] = argValue(v)
+	}
+	return args, nil
+}
+
+// insertBatchSize returns the number of rows to insert by one statement, see InsertRowsBatch.
+func insertBatchSize(DBType byte, columns int) int {
+	batch := InsertRowsBatch
+	if maxParams := GetDialect(DBType).MaxParams(); maxParams > 0 && columns > 0 && maxParams/columns < batch {
+		batch = maxParams / columns
+	}
+	if batch < 1 {
+		batch = 1
+	}
+	return batch
+}
+
+// argValue returns the value of anyT to pass as an argument to Go sql functions.
+func argValue(v anyT) interface{} {
+
+	const (
+		I = 0
+		B = 1
+		F = 2
+		S = 3
+		N = 4
+	)
+
+	switch v.t {
+	case I:
+		return v.i
+	case B:
+		return v.b
+	case F:
+		return v.f
+	case S:
+		return v.s
+	}
+	return nil
 }
@@ -1,6 +1,7 @@
 package sqla
 
 import (
+	"context"
 	"database/sql"
 	"testing"
 )
@@ -25,3 +26,87 @@ func TestInsertObject(t *testing.T) {
 	InsertObject(db, DBType, "documents", testobj)
 	db.Close()
 }
+
+func TestInsertObjectContext(t *testing.T) {
+	var DBType byte
+	DBType = SQLITE
+	var db *sql.DB
+	db = OpenSQLConnection(DBType, "file::memory:?cache=shared&_foreign_keys=true")
+	db.Exec("CREATE TABLE books (ID INTEGER PRIMARY KEY, BookTitle TEXT NOT NULL);")
+	defer db.Close()
+
+	var args AnyTslice
+	args = args.AppendNonEmptyString("BookTitle", "Some Book")
+	lastid, rowsaff, err := InsertObjectContext(context.Background(), db, DBType, "books", args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lastid == 0 || rowsaff != 1 {
+		t.Errorf("Expected:%s, received:%d, %d", "non-zero ID and 1 affected row", lastid, rowsaff)
+	}
+
+	args = nil
+	args = args.AppendNil("BookTitle")
+	_, _, err = InsertObjectContext(context.Background(), db, DBType, "books", args)
+	if err == nil {
+		t.Errorf("Expected an error on NOT NULL constraint, received nil")
+	}
+}
+
+func TestInsertObjects(t *testing.T) {
+	var DBType byte
+	DBType = SQLITE
+	var db *sql.DB
+	db = OpenSQLConnection(DBType, "file:insertobjectstest?mode=memory&cache=shared")
+	defer db.Close()
+	db.Exec("CREATE TABLE apps (ID INTEGER PRIMARY KEY, AppName TEXT NOT NULL, Version INTEGER);")
+
+	var rows []AnyTslice
+	for i := 1; i <= 3; i++ {
+		var args AnyTslice
+		if i == 2 {
+			args = args.AppendInt("Version", i)
+			args = args.AppendNonEmptyString("AppName", "app")
+		} else {
+			args = args.AppendNonEmptyString("AppName", "app")
+			args = args.AppendInt("Version", i)
+		}
+		rows = append(rows, args)
+	}
+	ids, rowsaff, err := InsertObjectsContext(context.Background(), db, DBType, "apps", rows, true)
+	if err != nil || rowsaff != 3 || len(ids) != 3 {
+		t.Errorf("Expected:%d, received:%d, %v, %v", 3, rowsaff, ids, err)
+	}
+	var version int
+	db.QueryRow("SELECT Version FROM apps WHERE ID = $1", ids[1]).Scan(&version)
+	if version != 2 {
+		t.Errorf("Expected:%d, received:%d", 2, version)
+	}
+
+	const SMALLDB = 101
+	RegisterDialect(SMALLDB, smallSQLiteDialect{})
+	ids, rowsaff = InsertObjects(db, SMALLDB, "apps", rows, false)
+	if rowsaff != 3 || len(ids) != 3 {
+		t.Errorf("Expected:%d, received:%d, %v", 3, rowsaff, ids)
+	}
+
+	rows[2] = rows[2].AppendNil("Notes")
+	if _, _, err = InsertObjectsContext(context.Background(), db, DBType, "apps", rows, false); err == nil {
+		t.Errorf("Expected an error on rows with different columns, received nil")
+	}
+	if _, _, err = InsertObjectsContext(context.Background(), db, DBType, "apps", []AnyTslice{{}, {}}, false); err == nil {
+		t.Errorf("Expected an error on rows without columns, received nil")
+	}
+	rows[2] = rows[2][:2]
+	rows[1] = nil
+	rows[1] = rows[1].AppendNil("AppName")
+	rows[1] = rows[1].AppendInt("Version", 2)
+	if _, _, err = InsertObjectsContext(context.Background(), db, DBType, "apps", rows, true); err == nil {
+		t.Errorf("Expected an error on NOT NULL constraint, received nil")
+	}
+	var count int
+	db.QueryRow("SELECT COUNT(*) FROM apps").Scan(&count)
+	if count != 6 {
+		t.Errorf("Expected:%d, received:%d", 6, count)
+	}
+}
@@ -0,0 +1,151 @@
+package sqla
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// PolicyAction is an action with rows of a table checked by Policy.
+type PolicyAction byte
+
+// ActionRead - select rows, it is checked for list pages (see Filter.Subject);
+// ActionUpdate - update rows;
+// ActionDelete - delete rows.
+const (
+	ActionRead PolicyAction = iota
+	ActionUpdate
+	ActionDelete
+)
+
+// Subject is the user whose permissions are checked. UserID is compared with owner columns and member column of membership tables.
+// Admin users are permitted any action with any rows.
+type Subject struct {
+	UserID interface{}
+	Admin  bool
+}
+
+// Membership grants access to rows through a membership table, e.g. rows of documents which Department is one of the user's groups:
+//
+//	sqla.Membership{Table: "user_groups", UserColumn: "UserID", GroupColumn: "GroupID", RowColumn: "Department"}
+//
+// Actions are the actions permitted this way, if empty all actions are permitted.
+type Membership struct {
+	Table       string
+	UserColumn  string
+	GroupColumn string
+	RowColumn   string
+	Actions     []PolicyAction
+}
+
+// Policy defines which rows of a table a user may read, update or delete, see TableMeta.Policy.
+// An action is permitted if the user ID is in one of OwnerColumns (and OwnerActions contains the action or is empty),
+// or if it is permitted by one of Memberships. If no rule permits an action, it is not permitted for any row except for admin users.
+type Policy struct {
+	OwnerColumns []string
+	OwnerActions []PolicyAction
+	Memberships  []Membership
+}
+
+// permits reports whether actions list permits action, empty list permits all actions.
+func permits(actions []PolicyAction, action PolicyAction) bool {
+	if len(actions) == 0 {
+		return true
+	}
+	for _, a := range actions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// buildPolicy adds to sq 'WHERE/AND (table.owner = $1 OR table.column IN (SELECT membership.group FROM membership WHERE membership.user = $2))' condition
+// which selects rows the subject may use for the action. Columns are qualified with table names, so the condition is not ambiguous if other tables are joined. Nothing is added if the table has no policy or the subject is admin.
+func (m TableMeta) buildPolicy(DBType byte, sq string, argsCounter int, subject Subject, action PolicyAction) (counter int, resquery string, args []interface{}) {
+	if m.Policy == nil || subject.Admin {
+		return argsCounter, sq, nil
+	}
+	var conditions []string
+	if permits(m.Policy.OwnerActions, action) {
+		for _, col := range m.Policy.OwnerColumns {
+			argsCounter++
+			conditions = append(conditions, m.Name+"."+col+" = "+MakeParam(DBType, argsCounter))
+			args = append(args, subject.UserID)
+		}
+	}
+	for _, ms := range m.Policy.Memberships {
+		if permits(ms.Actions, action) {
+			argsCounter++
+			conditions = append(conditions, m.Name+"."+ms.RowColumn+" IN (SELECT "+ms.Table+"."+ms.GroupColumn+" FROM "+ms.Table+" WHERE "+ms.Table+"."+ms.UserColumn+" = "+MakeParam(DBType, argsCounter)+")")
+			args = append(args, subject.UserID)
+		}
+	}
+	if len(conditions) == 0 {
+		conditions = append(conditions, "1 = 0")
+	}
+	if strings.Contains(sq, "WHERE") {
+		sq += "AND "
+	} else {
+		sq += "WHERE "
+	}
+	sq += "(" + strings.Join(conditions, " OR ") + ") "
+	return argsCounter, sq, args
+}
+
+// VerifyPermissions checks if the subject may perform the action with all objects which key ('ID' unless other is registered with RegisterTable) is present in keys list,
+// according to Policy registered with RegisterTable. Objects which do not exist are not permitted. Tables without policy permit everything.
+// Any error is only logged and false is returned, use VerifyPermissionsContext to receive it.
+func VerifyPermissions[K comparable](db Executor, DBType byte, table string, subject Subject, action PolicyAction, keys []K) bool {
+	permitted, err := VerifyPermissionsContext(context.Background(), db, DBType, table, subject, action, keys)
+	if err != nil {
+		log.Println(err)
+	}
+	return permitted
+}
+
+// VerifyPermissionsContext does the same as VerifyPermissions, but it takes a context and returns an error instead of logging it.
+func VerifyPermissionsContext[K comparable](ctx context.Context, db Executor, DBType byte, table string, subject Subject, action PolicyAction, keys []K) (bool, error) {
+	if err := ValidateColumns(table); err != nil {
+		return false, fmt.Errorf("%s: %w", currentFunction(), err)
+	}
+	return verifyPermissions(ctx, db, DBType, currentFunction(), GetTableMeta(table), subject, action, keys)
+}
+
+// verifyPermissions counts rows with keys which the subject may use according to meta.Policy and compares the count with the number of keys.
+// Errors are wrapped with the name of the calling function (fname).
+func verifyPermissions[K comparable](ctx context.Context, db Executor, DBType byte, fname string, meta TableMeta, subject Subject, action PolicyAction, keys []K) (bool, error) {
+	if meta.Policy == nil || subject.Admin {
+		return true, nil
+	}
+	keyColumn, err := meta.keyColumn()
+	if err != nil {
+		return false, wrapError(fname, err)
+	}
+	var distinct []K
+	seen := map[K]bool{}
+	for _, k := range keys {
+		if !seen[k] {
+			seen[k] = true
+			distinct = append(distinct, k)
+		}
+	}
+	reserved := len(meta.Policy.OwnerColumns) + len(meta.Policy.Memberships)
+	for _, chunk := range chunkValues(DBType, distinct, reserved) {
+		argsCounter, sq, args := BuildSQLINOf(DBType, "SELECT COUNT(*) FROM "+meta.Name+" ", 0, keyColumn, chunk)
+		_, sq, argstoAppend := meta.buildPolicy(DBType, sq, argsCounter, subject, action)
+		args = append(args, argstoAppend...)
+		if DEBUG {
+			log.Println(sq, args)
+		}
+		var count int
+		if err = db.QueryRowContext(ctx, sq, args...).Scan(&count); err != nil {
+			return false, wrapError(fname, err)
+		}
+		if count != len(chunk) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,69 @@
+package sqla
+
+import (
+	"database/sql"
+	"testing"
+)
+
+func TestPolicy(t *testing.T) {
+	var DBType byte
+	DBType = SQLITE
+	var db *sql.DB
+	db = OpenSQLConnection(DBType, "file:policytest?mode=memory&cache=shared")
+	defer db.Close()
+	db.Exec("CREATE TABLE reports (ID INTEGER PRIMARY KEY, Creator INTEGER, Assignee INTEGER, Department INTEGER);")
+	db.Exec("CREATE TABLE user_groups (UserID INTEGER, GroupID INTEGER);")
+	db.Exec("INSERT INTO user_groups (UserID, GroupID) VALUES (2, 10);")
+	RegisterTable(TableMeta{Name: "reports", Policy: &Policy{
+		OwnerColumns: []string{"Creator", "Assignee"},
+		Memberships: []Membership{{Table: "user_groups", UserColumn: "UserID", GroupColumn: "GroupID", RowColumn: "Department",
+			Actions: []PolicyAction{ActionRead}}},
+	}})
+
+	var ids []int
+	for _, row := range [][3]int{{1, 0, 10}, {1, 2, 20}, {3, 0, 10}, {3, 0, 30}} {
+		var args AnyTslice
+		args = args.AppendInt("Creator", row[0])
+		args = args.AppendInt("Assignee", row[1])
+		args = args.AppendInt("Department", row[2])
+		ID, _ := InsertObject(db, DBType, "reports", args)
+		ids = append(ids, ID)
+	}
+
+	count := func(F Filter) (n int) {
+		_, sqcount, _, argscount := ConstructSELECTquery(DBType, "reports", "*", "ID", "", F, "ID", 0, 0, 0, false, Seek{})
+		db.QueryRow(sqcount, argscount...).Scan(&n)
+		return n
+	}
+	if n := count(Filter{Subject: &Subject{UserID: 2}}); n != 3 {
+		t.Errorf("Expected:%d, received:%d", 3, n)
+	}
+	if n := count(Filter{Subject: &Subject{UserID: 2, Admin: true}}); n != 4 {
+		t.Errorf("Expected:%d, received:%d", 4, n)
+	}
+	if n := count(Filter{Subject: &Subject{UserID: 5}}); n != 0 {
+		t.Errorf("Expected:%d, received:%d", 0, n)
+	}
+
+	sq, args, err := NewSelectBuilder(POSTGRESQL).From("reports").Permit(Subject{UserID: 2}, ActionDelete).Build()
+	expected := "SELECT * FROM reports  WHERE (reports.Creator = $1 OR reports.Assignee = $2) "
+	if err != nil || sq != expected || len(args) != 2 {
+		t.Errorf("Expected:%s, received:%s, %v, %v", expected, sq, args, err)
+	}
+
+	if !VerifyPermissions(db, DBType, "reports", Subject{UserID: 2}, ActionRead, []int{ids[0], ids[1], ids[2], ids[1]}) {
+		t.Errorf("Expected:%t, received:%t", true, false)
+	}
+	if VerifyPermissions(db, DBType, "reports", Subject{UserID: 2}, ActionUpdate, []int{ids[0], ids[1]}) {
+		t.Errorf("Expected:%t, received:%t", false, true)
+	}
+	if VerifyPermissions(db, DBType, "reports", Subject{UserID: 1}, ActionDelete, []int{ids[0], 100}) {
+		t.Errorf("Expected:%t, received:%t", false, true)
+	}
+	if !VerifyRemovalPermissions(db, DBType, "reports", 3, false, true, ids[2:]) {
+		t.Errorf("Expected:%t, received:%t", true, false)
+	}
+	if VerifyRemovalPermissions(db, DBType, "reports", 3, false, true, ids[1:]) {
+		t.Errorf("Expected:%t, received:%t", false, true)
+	}
+}
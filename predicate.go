@@ -0,0 +1,265 @@
+package sqla
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// PredicateAnd, PredicateOr and PredicateNot are operators of Predicate. Predicate with empty Op is a leaf condition.
+const (
+	PredicateAnd = "and"
+	PredicateOr  = "or"
+	PredicateNot = "not"
+)
+
+// ErrInvalidPredicate is returned if Predicate has unknown operator, or a leaf without exactly one condition, or a leaf condition without values.
+var ErrInvalidPredicate = errors.New("invalid predicate")
+
+// TextCondition searches for Text in any of Columns the same way as Filter.TextFilter does.
+type TextCondition struct {
+	Name    string
+	Text    string
+	Columns []string
+}
+
+// Predicate is a node of a boolean expression tree to filter rows by any combination of conditions, e.g. A AND (B OR C) AND NOT D,
+// which cannot be expressed by ClassFilter and ClassFilterOR. Set it to Filter.Predicate, it is ANDed with other filters.
+//
+// Op is PredicateAnd, PredicateOr or PredicateNot for a node with Args, or empty for a leaf which has exactly one of Class, StringClass, Null, Bool, Date, Sum or Text condition.
+// The condition of a leaf should have values (e.g. non-empty List of ClassFilter, Dates of DateFilter, Text and Columns of TextCondition).
+// PredicateNot negates the conjunction of its Args. Empty AND is true, empty OR and empty NOT are false.
+// Predicate has JSON representation, e.g. {"Op":"not","Args":[{"Class":{"Name":"statuses","List":[3]}}]}, dates and sums are converted from strings
+// by GetFilterFromJSON and FilterSchema.FilterFromJSON.
+type Predicate struct {
+	Op          string             `json:",omitempty"`
+	Args        []Predicate        `json:",omitempty"`
+	Class       *ClassFilter       `json:",omitempty"`
+	StringClass *StringClassFilter `json:",omitempty"`
+	Null        *NullFilter        `json:",omitempty"`
+	Bool        *BoolFilter        `json:",omitempty"`
+	Date        *DateFilter        `json:",omitempty"`
+	Sum         *SumFilter         `json:",omitempty"`
+	Text        *TextCondition     `json:",omitempty"`
+}
+
+// And returns Predicate which is true if all of args are true.
+func And(args ...Predicate) Predicate {
+	return Predicate{Op: PredicateAnd, Args: args}
+}
+
+// Or returns Predicate which is true if any of args is true.
+func Or(args ...Predicate) Predicate {
+	return Predicate{Op: PredicateOr, Args: args}
+}
+
+// Not returns Predicate which is true if arg is false.
+func Not(arg Predicate) Predicate {
+	return Predicate{Op: PredicateNot, Args: []Predicate{arg}}
+}
+
+// WhereClass returns leaf Predicate with ClassFilter condition.
+func WhereClass(FC ClassFilter) Predicate {
+	return Predicate{Class: &FC}
+}
+
+// WhereStringClass returns leaf Predicate with StringClassFilter condition.
+func WhereStringClass(FC StringClassFilter) Predicate {
+	return Predicate{StringClass: &FC}
+}
+
+// WhereNull returns leaf Predicate with NullFilter condition.
+func WhereNull(NF NullFilter) Predicate {
+	return Predicate{Null: &NF}
+}
+
+// WhereBool returns leaf Predicate with BoolFilter condition.
+func WhereBool(BF BoolFilter) Predicate {
+	return Predicate{Bool: &BF}
+}
+
+// WhereDate returns leaf Predicate with DateFilter condition.
+func WhereDate(DF DateFilter) Predicate {
+	return Predicate{Date: &DF}
+}
+
+// WhereSum returns leaf Predicate with SumFilter condition.
+func WhereSum(SF SumFilter) Predicate {
+	return Predicate{Sum: &SF}
+}
+
+// WhereText returns leaf Predicate with TextCondition.
+func WhereText(text string, columns ...string) Predicate {
+	return Predicate{Text: &TextCondition{Text: text, Columns: columns}}
+}
+
+// build adds to sq 'WHERE/AND (condition)' made of the predicate tree.
+func (p *Predicate) build(DBType byte, sq string, argsCounter int) (counter int, resquery string, args []interface{}) {
+	argsCounter, cond, args := p.condition(DBType, argsCounter)
+	if strings.Contains(sq, "WHERE") {
+		sq += "AND "
+	} else {
+		sq += "WHERE "
+	}
+	sq += cond + " "
+	return argsCounter, sq, args
+}
+
+// condition returns SQL condition of the predicate in parentheses (except for NOT and constants) numbering placeholders from argsCounter + 1.
+func (p *Predicate) condition(DBType byte, argsCounter int) (counter int, cond string, args []interface{}) {
+	switch p.Op {
+	case PredicateAnd, PredicateOr, PredicateNot:
+		if len(p.Args) == 0 {
+			if p.Op == PredicateAnd {
+				return argsCounter, "1 = 1", nil
+			}
+			// empty OR is false, and NOT of empty AND is false too
+			return argsCounter, "1 = 0", nil
+		}
+		var conds []string
+		for i := range p.Args {
+			var c string
+			var argstoAppend []interface{}
+			argsCounter, c, argstoAppend = p.Args[i].condition(DBType, argsCounter)
+			conds = append(conds, c)
+			args = append(args, argstoAppend...)
+		}
+		if p.Op == PredicateOr {
+			return argsCounter, "(" + strings.Join(conds, " OR ") + ")", args
+		}
+		cond = "(" + strings.Join(conds, " AND ") + ")"
+		if p.Op == PredicateNot {
+			if len(conds) == 1 {
+				cond = conds[0]
+			}
+			cond = "NOT " + cond
+		}
+		return argsCounter, cond, args
+	}
+
+	var sq string
+	switch {
+	case p.Class != nil:
+		argsCounter, sq, args = buildClassFilter(DBType, sq, argsCounter, *p.Class)
+	case p.StringClass != nil:
+		argsCounter, sq, args = buildStringClassFilter(DBType, sq, argsCounter, *p.StringClass)
+	case p.Null != nil:
+		argsCounter, sq, args = buildNullFilter(DBType, sq, argsCounter, *p.Null)
+	case p.Bool != nil:
+		argsCounter, sq, args = buildBoolFilter(DBType, sq, argsCounter, *p.Bool)
+	case p.Date != nil:
+		argsCounter, sq, args = buildDateFilter(DBType, sq, argsCounter, *p.Date)
+	case p.Sum != nil:
+		argsCounter, sq, args = buildSumFilter(DBType, sq, argsCounter, *p.Sum)
+	case p.Text != nil:
+		argsCounter, sq, args = buildTextFilter(DBType, sq, argsCounter, p.Text.Text, p.Text.Columns)
+	}
+	sq = strings.TrimSpace(strings.TrimPrefix(sq, "WHERE "))
+	if sq == "" {
+		return argsCounter, "1 = 1", args
+	}
+	return argsCounter, "(" + sq + ")", args
+}
+
+// validate checks operators and leaves of the predicate tree.
+func (p *Predicate) validate() error {
+	switch p.Op {
+	case PredicateAnd, PredicateOr, PredicateNot:
+		for i := range p.Args {
+			if err := p.Args[i].validate(); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "":
+		var n int
+		for _, set := range []bool{p.Class != nil, p.StringClass != nil, p.Null != nil, p.Bool != nil, p.Date != nil, p.Sum != nil, p.Text != nil} {
+			if set {
+				n++
+			}
+		}
+		if n != 1 || len(p.Args) > 0 {
+			return fmt.Errorf("%w: leaf should have one condition", ErrInvalidPredicate)
+		}
+		if p.empty() {
+			// an empty leaf would be true, and NOT of it would filter out all rows
+			return fmt.Errorf("%w: leaf condition has no values", ErrInvalidPredicate)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: unknown operator %q", ErrInvalidPredicate, p.Op)
+}
+
+// empty reports whether the leaf condition has no values to compare with, e.g. DateFilter without dates.
+func (p *Predicate) empty() bool {
+	switch {
+	case p.Class != nil:
+		return len(p.Class.List) == 0
+	case p.StringClass != nil:
+		return len(p.StringClass.List) == 0
+	case p.Date != nil:
+		return len(p.Date.Dates) == 0
+	case p.Sum != nil:
+		return len(p.Sum.Sums) == 0
+	case p.Text != nil:
+		return p.Text.Text == "" || len(p.Text.Columns) == 0
+	}
+	return false
+}
+
+// walk calls fn for every leaf of the predicate tree.
+func (p *Predicate) walk(fn func(leaf *Predicate)) {
+	if p.Op != "" {
+		for i := range p.Args {
+			p.Args[i].walk(fn)
+		}
+		return
+	}
+	fn(p)
+}
+
+// mapColumns returns a copy of the predicate tree with every column name replaced by fn.
+func (p Predicate) mapColumns(fn func(col string) string) Predicate {
+	if p.Op != "" {
+		args := make([]Predicate, len(p.Args))
+		for i := range p.Args {
+			args[i] = p.Args[i].mapColumns(fn)
+		}
+		p.Args = args
+		return p
+	}
+	switch {
+	case p.Class != nil:
+		c := *p.Class
+		c.Column = fn(c.Column)
+		p.Class = &c
+	case p.StringClass != nil:
+		c := *p.StringClass
+		c.Column = fn(c.Column)
+		p.StringClass = &c
+	case p.Null != nil:
+		c := *p.Null
+		c.Column = fn(c.Column)
+		p.Null = &c
+	case p.Bool != nil:
+		c := *p.Bool
+		c.Column = fn(c.Column)
+		p.Bool = &c
+	case p.Date != nil:
+		c := *p.Date
+		c.Column = fn(c.Column)
+		p.Date = &c
+	case p.Sum != nil:
+		c := *p.Sum
+		c.Column, c.CurrencyColumn = fn(c.Column), fn(c.CurrencyColumn)
+		p.Sum = &c
+	case p.Text != nil:
+		c := *p.Text
+		c.Columns = make([]string, len(p.Text.Columns))
+		for i, col := range p.Text.Columns {
+			c.Columns[i] = fn(col)
+		}
+		p.Text = &c
+	}
+	return p
+}
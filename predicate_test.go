@@ -0,0 +1,75 @@
+package sqla
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+func TestPredicate(t *testing.T) {
+	p := And(
+		WhereClass(ClassFilter{Column: "Status", List: []int{1, 2}}),
+		Or(WhereSum(SumFilter{Column: "Sum", Relation: "gt", Sums: []int{100}}), WhereText("abc", "Title")),
+		Not(WhereDate(DateFilter{Column: "Created", Dates: []int64{10, 20}})),
+	)
+	F := Filter{ClassFilter: []ClassFilter{{Column: "DocType", List: []int{5}}}, Predicate: &p}
+
+	var tests = []struct {
+		DBType   byte
+		expected string
+	}{
+		{POSTGRESQL, "SELECT * FROM documents  WHERE DocType IN ($1) AND ((Status IN ($2, $3)) AND ((Sum > $4) OR ((Title ILIKE $5 ))) AND NOT (Created BETWEEN $6 AND $7)) "},
+		{MYSQL, "SELECT * FROM documents  WHERE DocType IN (?) AND ((Status IN (?, ?)) AND ((Sum > ?) OR ((Title LIKE ? ))) AND NOT (Created BETWEEN ? AND ?)) "},
+	}
+	for _, tt := range tests {
+		sq, args, err := NewSelectBuilder(tt.DBType).From("documents").Where(F).Build()
+		if err != nil || sq != tt.expected || len(args) != 7 || args[4] != "%abc%" {
+			t.Errorf("Expected:%s, received:%s, %v, %v", tt.expected, sq, args, err)
+		}
+	}
+
+	var DBType byte
+	DBType = SQLITE
+	var db *sql.DB
+	db = OpenSQLConnection(DBType, "file:predicatetest?mode=memory&cache=shared")
+	defer db.Close()
+	db.Exec("CREATE TABLE tickets (ID INTEGER PRIMARY KEY, Status INTEGER, Priority INTEGER);")
+	for _, row := range [][2]int{{1, 1}, {1, 2}, {2, 1}, {2, 3}, {3, 3}} {
+		var args AnyTslice
+		args = args.AppendInt("Status", row[0])
+		args = args.AppendInt("Priority", row[1])
+		InsertObject(db, DBType, "tickets", args)
+	}
+	JSON := `{"Predicate":{"Op":"or","Args":[
+{"Op":"and","Args":[{"Class":{"Column":"Status","List":[1]}},{"Op":"not","Args":[{"Class":{"Column":"Priority","List":[1]}}]}]},
+{"Class":{"Column":"Priority","List":[3]}}]}}`
+	F = Filter{}
+	F.GetFilterFromJSON([]byte(JSON), dateTimeToInt64, dateTimeToInt64)
+	sq, args, err := NewSelectBuilder(DBType).From("tickets").Count("*").Where(F).BuildCount()
+	var n int
+	if err == nil {
+		err = db.QueryRow(sq, args...).Scan(&n)
+	}
+	if err != nil || n != 3 {
+		t.Errorf("Expected:%d, received:%d, %v", 3, n, err)
+	}
+
+	F.Predicate = &Predicate{Op: PredicateNot}
+	sq, _, err = NewSelectBuilder(POSTGRESQL).From("tickets").Where(F).Build()
+	if expected := "SELECT * FROM tickets  WHERE 1 = 0 "; err != nil || sq != expected {
+		t.Errorf("Expected:%s, received:%s, %v", expected, sq, err)
+	}
+
+	for _, leaf := range []Predicate{WhereDate(DateFilter{Column: "Created"}), WhereText("", "Title"), WhereClass(ClassFilter{Column: "Status"})} {
+		p := Not(leaf)
+		F.Predicate = &p
+		if _, _, err = NewSelectBuilder(DBType).From("tickets").Where(F).Build(); !errors.Is(err, ErrInvalidPredicate) {
+			t.Errorf("Expected:%v, received:%v", ErrInvalidPredicate, err)
+		}
+	}
+
+	F.Predicate = &Predicate{Op: "xor"}
+	if _, _, err = NewSelectBuilder(DBType).From("tickets").Where(F).Build(); !errors.Is(err, ErrInvalidPredicate) {
+		t.Errorf("Expected:%v, received:%v", ErrInvalidPredicate, err)
+	}
+}
@@ -0,0 +1,178 @@
+package sqla
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+)
+
+// ErrRestricted is the kind of *RestrictError, use it with errors.Is.
+var ErrRestricted = errors.New("delete is restricted by referencing rows")
+
+// RestrictError is returned by DeleteWithDependents if there are rows referencing deleted ones by a relation with OnDeleteRestrict action.
+type RestrictError struct {
+	Table  string
+	Column string
+	Count  int
+}
+
+func (e *RestrictError) Error() string {
+	return fmt.Sprintf("%s: %d rows in table %s (column %s)", ErrRestricted, e.Count, e.Table, e.Column)
+}
+
+// Is reports if target is ErrRestricted.
+func (e *RestrictError) Is(target error) bool {
+	return target == ErrRestricted
+}
+
+// DeleteWithDependents deletes objects which key ('ID' unless other is registered with RegisterTable) is present in ids list,
+// and processes the rows referencing them according to Relations registered with RegisterTable: child rows are deleted (recursively with their own dependents),
+// or the referencing column is set to NULL, or nothing is deleted if there are rows of OnDeleteRestrict relation (then *RestrictError is returned).
+// Everything is done using DeleteObjects and SetToNull, so soft delete settings of the tables are respected. If db is *sql.DB, it is done in one transaction,
+// otherwise the statements are executed using db, e.g. in the transaction of the caller. It returns the number of affected rows for each table.
+// Any error is only logged, use DeleteWithDependentsContext to receive it.
+func DeleteWithDependents(db Executor, DBType byte, table string, ids []int) (counts map[string]int) {
+	counts, err := DeleteWithDependentsContext(context.Background(), db, DBType, table, ids)
+	if err != nil {
+		log.Println(err)
+	}
+	return counts
+}
+
+// DeleteWithDependentsContext does the same as DeleteWithDependents, but it takes a context and returns an error instead of logging it.
+func DeleteWithDependentsContext(ctx context.Context, db Executor, DBType byte, table string, ids []int) (counts map[string]int, err error) {
+	if err = ValidateColumns(table); err != nil {
+		return nil, fmt.Errorf("%s: %w", currentFunction(), err)
+	}
+	if sqldb, ok := db.(*sql.DB); ok {
+		err = WithTxContext(ctx, sqldb, nil, func(tx *sql.Tx) error {
+			counts = map[string]int{}
+			return deleteDependents(ctx, tx, DBType, table, ids, counts, map[string]map[int]bool{})
+		})
+	} else {
+		counts = map[string]int{}
+		err = deleteDependents(ctx, db, DBType, table, ids, counts, map[string]map[int]bool{})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", currentFunction(), err)
+	}
+	return counts, nil
+}
+
+// deleteDependents processes relations of the table and deletes its rows. visited contains keys of the rows already processed for each table to stop on reference cycles.
+func deleteDependents(ctx context.Context, db Executor, DBType byte, table string, ids []int, counts map[string]int, visited map[string]map[int]bool) error {
+	if visited[table] == nil {
+		visited[table] = map[int]bool{}
+	}
+	var newIDs []int
+	for _, id := range ids {
+		if !visited[table][id] {
+			visited[table][id] = true
+			newIDs = append(newIDs, id)
+		}
+	}
+	if len(newIDs) == 0 {
+		return nil
+	}
+	meta := GetTableMeta(table)
+	keyColumn, err := meta.keyColumn()
+	if err != nil {
+		return fmt.Errorf("table %s: %w", table, err)
+	}
+
+	for _, rel := range meta.Relations {
+		if rel.Action != OnDeleteRestrict {
+			continue
+		}
+		n, err := countReferencing(ctx, db, DBType, rel, newIDs)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			return &RestrictError{Table: rel.Table, Column: rel.Column, Count: n}
+		}
+	}
+
+	for _, rel := range meta.Relations {
+		var rowsaff int
+		switch rel.Action {
+		case OnDeleteCascade:
+			if len(GetTableMeta(rel.Table).Relations) > 0 {
+				childIDs, err := selectReferencing(ctx, db, DBType, rel, newIDs)
+				if err != nil {
+					return err
+				}
+				if err = deleteDependents(ctx, db, DBType, rel.Table, childIDs, counts, visited); err != nil {
+					return err
+				}
+				continue
+			}
+			rowsaff, err = DeleteObjectsContext(ctx, db, DBType, rel.Table, rel.Column, newIDs)
+		case OnDeleteSetNull:
+			rowsaff, err = SetToNullContext(ctx, db, DBType, rel.Table, rel.Column, newIDs)
+		}
+		if err != nil {
+			return err
+		}
+		counts[rel.Table] += rowsaff
+	}
+
+	rowsaff, err := DeleteObjectsContext(ctx, db, DBType, table, keyColumn, newIDs)
+	counts[table] += rowsaff
+	return err
+}
+
+// countReferencing returns the number of rows of the relation child table referencing ids.
+func countReferencing(ctx context.Context, db Executor, DBType byte, rel Relation, ids []int) (count int, err error) {
+	meta := GetTableMeta(rel.Table)
+	for _, chunk := range chunkValues(DBType, ids, 1) {
+		argsCounter, sq, args := BuildSQLIN(DBType, "SELECT COUNT(*) FROM "+rel.Table+" ", 0, rel.Column, chunk)
+		_, sq, args = meta.buildDeleteCondition(DBType, sq, argsCounter, args)
+		if DEBUG {
+			log.Println(sq, args)
+		}
+		var n int
+		if err = db.QueryRowContext(ctx, sq, args...).Scan(&n); err != nil {
+			return 0, wrapError(currentFunction(), err)
+		}
+		count += n
+	}
+	return count, nil
+}
+
+// selectReferencing returns keys of the rows of the relation child table referencing ids.
+func selectReferencing(ctx context.Context, db Executor, DBType byte, rel Relation, ids []int) (keys []int, err error) {
+	keyColumn, err := GetTableMeta(rel.Table).keyColumn()
+	if err != nil {
+		return nil, fmt.Errorf("table %s: %w", rel.Table, err)
+	}
+	for _, chunk := range chunkValues(DBType, ids, 0) {
+		_, sq, args := BuildSQLIN(DBType, "SELECT "+keyColumn+" FROM "+rel.Table+" ", 0, rel.Column, chunk)
+		if DEBUG {
+			log.Println(sq, args)
+		}
+		rows, err := db.QueryContext(ctx, sq, args...)
+		if err != nil {
+			return nil, wrapError(currentFunction(), err)
+		}
+		for rows.Next() {
+			var key sql.NullInt64
+			if err = rows.Scan(&key); err != nil {
+				rows.Close()
+				return nil, wrapError(currentFunction(), err)
+			}
+			if !key.Valid {
+				continue
+			}
+			keys = append(keys, int(key.Int64))
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, wrapError(currentFunction(), err)
+		}
+	}
+	return keys, nil
+}
@@ -0,0 +1,70 @@
+package sqla
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+func TestDeleteWithDependents(t *testing.T) {
+	var DBType byte
+	DBType = SQLITE
+	var db *sql.DB
+	db = OpenSQLConnection(DBType, "file:relationstest?mode=memory&cache=shared")
+	defer db.Close()
+	db.Exec("CREATE TABLE folders (ID INTEGER PRIMARY KEY, Name TEXT);")
+	db.Exec("CREATE TABLE files (ID INTEGER PRIMARY KEY, Folder INTEGER);")
+	db.Exec("CREATE TABLE versions (ID INTEGER PRIMARY KEY, File INTEGER);")
+	db.Exec("CREATE TABLE shortcuts (ID INTEGER PRIMARY KEY, Folder INTEGER);")
+	db.Exec("CREATE TABLE holds (ID INTEGER PRIMARY KEY, Folder INTEGER);")
+	RegisterTable(TableMeta{Name: "folders", Relations: []Relation{
+		{Table: "files", Column: "Folder", Action: OnDeleteCascade},
+		{Table: "shortcuts", Column: "Folder", Action: OnDeleteSetNull},
+		{Table: "holds", Column: "Folder", Action: OnDeleteRestrict},
+	}})
+	RegisterTable(TableMeta{Name: "files", Relations: []Relation{
+		{Table: "versions", Column: "File", Action: OnDeleteCascade},
+	}})
+
+	db.Exec("INSERT INTO folders (ID, Name) VALUES (1, 'a'), (2, 'b');")
+	db.Exec("INSERT INTO files (ID, Folder) VALUES (10, 1), (11, 1), (12, 2);")
+	db.Exec("INSERT INTO versions (ID, File) VALUES (100, 10), (101, 10), (102, 11), (103, 12);")
+	db.Exec("INSERT INTO shortcuts (ID, Folder) VALUES (200, 1);")
+	db.Exec("INSERT INTO holds (ID, Folder) VALUES (300, 2);")
+
+	_, err := DeleteWithDependentsContext(context.Background(), db, DBType, "folders", []int{1, 2})
+	var re *RestrictError
+	if !errors.Is(err, ErrRestricted) || !errors.As(err, &re) || re.Table != "holds" || re.Count != 1 {
+		t.Errorf("Expected:%v, received:%v", ErrRestricted, err)
+	}
+	var n int
+	db.QueryRow("SELECT COUNT(*) FROM versions").Scan(&n)
+	if n != 4 {
+		t.Errorf("Expected:%d, received:%d", 4, n)
+	}
+
+	counts := DeleteWithDependents(db, DBType, "folders", []int{1})
+	expected := map[string]int{"folders": 1, "files": 2, "versions": 3, "shortcuts": 1}
+	for table, count := range expected {
+		if counts[table] != count {
+			t.Errorf("Expected:%s %d, received:%d", table, count, counts[table])
+		}
+	}
+	var folder sql.NullInt64
+	db.QueryRow("SELECT Folder FROM shortcuts WHERE ID = 200").Scan(&folder)
+	if folder.Valid {
+		t.Errorf("Expected:%s, received:%d", "NULL", folder.Int64)
+	}
+
+	err = WithTx(db, func(tx *sql.Tx) error {
+		if _, err := tx.Exec("DELETE FROM holds WHERE ID = 300"); err != nil {
+			return err
+		}
+		counts, err = DeleteWithDependentsContext(context.Background(), tx, DBType, "folders", []int{2})
+		return err
+	})
+	if err != nil || counts["folders"] != 1 || counts["files"] != 1 || counts["versions"] != 1 {
+		t.Errorf("Expected:%s, received:%v, %v", "folder 2 deleted in transaction", counts, err)
+	}
+}
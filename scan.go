@@ -0,0 +1,288 @@
+package sqla
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SelectInto executes a query and scans the result into dest, which should be a pointer to a slice of structs (or of pointers to structs),
+// or a pointer to a struct to get only the first row (then sql.ErrNoRows is returned if there are no rows).
+// Result columns are mapped to struct fields the same way as in StructToAnyTslice (by `sqla` tags or field names), the case of column names is ignored.
+// Columns without a matching field are skipped.
+//
+// NULL values are converted to zero values, or to nil for pointer fields.
+// JSON made with AppendJSONList or AppendJSONListInt is decoded back into []string or []int fields, fields tagged with json option are decoded from JSON of any kind.
+func SelectInto(db Executor, dest interface{}, query string, args ...interface{}) error {
+	return SelectIntoContext(context.Background(), db, dest, query, args...)
+}
+
+// SelectIntoContext does the same as SelectInto, but it takes a context to cancel the query or to set a timeout for it.
+func SelectIntoContext(ctx context.Context, db Executor, dest interface{}, query string, args ...interface{}) error {
+	dv := reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Ptr || dv.IsNil() {
+		return fmt.Errorf("%s: dest should be a non-nil pointer", currentFunction())
+	}
+	dv = dv.Elem()
+	single := dv.Kind() == reflect.Struct
+	var elemType reflect.Type
+	if single {
+		elemType = dv.Type()
+	} else if dv.Kind() == reflect.Slice {
+		elemType = dv.Type().Elem()
+		if elemType.Kind() == reflect.Ptr {
+			elemType = elemType.Elem()
+		}
+	}
+	if elemType == nil || elemType.Kind() != reflect.Struct {
+		return fmt.Errorf("%s: dest should point to a struct or to a slice of structs", currentFunction())
+	}
+
+	if DEBUG {
+		log.Println(query, args)
+	}
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return wrapError(currentFunction(), err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return wrapError(currentFunction(), err)
+	}
+	fields := structFields(elemType)
+	mapping := make([]*fieldInfo, len(columns))
+	for i, col := range columns {
+		for j := range fields {
+			if strings.EqualFold(fields[j].column, col) {
+				mapping[i] = &fields[j]
+				break
+			}
+		}
+	}
+
+	values := make([]interface{}, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	found := false
+	for rows.Next() {
+		if err = rows.Scan(pointers...); err != nil {
+			return wrapError(currentFunction(), err)
+		}
+		elem := dv
+		if !single {
+			elem = reflect.New(elemType).Elem()
+		}
+		for i, f := range mapping {
+			if f == nil {
+				continue
+			}
+			if err = assignValue(elem.FieldByIndex(f.index), values[i], f.json); err != nil {
+				return fmt.Errorf("%s: column %s: %w", currentFunction(), columns[i], err)
+			}
+		}
+		found = true
+		if single {
+			break
+		}
+		if dv.Type().Elem().Kind() == reflect.Ptr {
+			elem = elem.Addr()
+		}
+		dv.Set(reflect.Append(dv, elem))
+	}
+	if err = rows.Err(); err != nil {
+		return wrapError(currentFunction(), err)
+	}
+	if single && !found {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetByID selects columns mapped to the fields of dest struct from the table and scans the row where key column ('ID' unless other is registered with RegisterTable) contains ID value.
+// dest should be a pointer to a struct, see SelectInto for mapping rules. sql.ErrNoRows is returned if there is no such row,
+// or if the row is soft-deleted (see TableMeta.SoftDeleteColumn).
+func GetByID(db Executor, DBType byte, table string, dest interface{}, ID int) error {
+	return GetByIDContext(context.Background(), db, DBType, table, dest, ID)
+}
+
+// GetByIDContext does the same as GetByID, but it takes a context to cancel the query or to set a timeout for it.
+func GetByIDContext(ctx context.Context, db Executor, DBType byte, table string, dest interface{}, ID int) error {
+	t := reflect.TypeOf(dest)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%s: dest should be a pointer to a struct", currentFunction())
+	}
+	if err := ValidateColumns(table); err != nil {
+		return fmt.Errorf("%s: %w", currentFunction(), err)
+	}
+	meta := GetTableMeta(table)
+	keyColumn, err := meta.keyColumn()
+	if err != nil {
+		return wrapError(currentFunction(), err)
+	}
+	var columns []string
+	for _, f := range structFields(t.Elem()) {
+		columns = append(columns, f.column)
+	}
+	sq := "SELECT " + strings.Join(columns, ", ") + " FROM " + table + " WHERE " + keyColumn + " = " + MakeParam(DBType, 1) + " "
+	args := []interface{}{ID}
+	if meta.SoftDeleteColumn != "" {
+		var argstoAppend []interface{}
+		_, sq, argstoAppend = meta.buildNotDeleted(DBType, sq, 1)
+		args = append(args, argstoAppend...)
+	}
+	return SelectIntoContext(ctx, db, dest, sq, args...)
+}
+
+var scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+
+// assignValue converts a value returned by a database driver and sets it to a struct field.
+func assignValue(fv reflect.Value, src interface{}, isJSON bool) error {
+	if fv.Addr().Type().Implements(scannerType) {
+		return fv.Addr().Interface().(sql.Scanner).Scan(src)
+	}
+	if src == nil {
+		fv.Set(reflect.Zero(fv.Type()))
+		return nil
+	}
+	if fv.Kind() == reflect.Ptr {
+		elem := reflect.New(fv.Type().Elem())
+		if err := assignValue(elem.Elem(), src, isJSON); err != nil {
+			return err
+		}
+		fv.Set(elem)
+		return nil
+	}
+
+	var text string
+	var isText bool
+	switch s := src.(type) {
+	case []byte:
+		text, isText = string(s), true
+	case string:
+		text, isText = s, true
+	}
+
+	if isJSON || (isText && fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() != reflect.Uint8) {
+		if !isText {
+			return fmt.Errorf("cannot decode JSON from %T", src)
+		}
+		if text == "" {
+			fv.Set(reflect.Zero(fv.Type()))
+			return nil
+		}
+		return json.Unmarshal([]byte(text), fv.Addr().Interface())
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		switch s := src.(type) {
+		case int64:
+			text = strconv.FormatInt(s, 10)
+		case float64:
+			text = strconv.FormatFloat(s, 'f', -1, 64)
+		case bool:
+			text = strconv.FormatBool(s)
+		case time.Time:
+			text = s.Format(time.RFC3339Nano)
+		}
+		fv.SetString(text)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := toInt64(src, text, isText)
+		if err != nil {
+			return err
+		}
+		fv.SetInt(i)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, err := toInt64(src, text, isText)
+		if err != nil {
+			return err
+		}
+		fv.SetUint(uint64(i))
+		return nil
+	case reflect.Float32, reflect.Float64:
+		switch s := src.(type) {
+		case float64:
+			fv.SetFloat(s)
+			return nil
+		case int64:
+			fv.SetFloat(float64(s))
+			return nil
+		}
+		if isText {
+			f, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				return err
+			}
+			fv.SetFloat(f)
+			return nil
+		}
+	case reflect.Bool:
+		switch s := src.(type) {
+		case bool:
+			fv.SetBool(s)
+			return nil
+		case int64:
+			fv.SetBool(s != 0)
+			return nil
+		}
+		if isText {
+			b, err := strconv.ParseBool(text)
+			if err != nil {
+				return err
+			}
+			fv.SetBool(b)
+			return nil
+		}
+	}
+
+	sv := reflect.ValueOf(src)
+	if sv.Type().AssignableTo(fv.Type()) {
+		fv.Set(sv)
+		return nil
+	}
+	if sv.Type().ConvertibleTo(fv.Type()) && sv.Kind() != reflect.String {
+		fv.Set(sv.Convert(fv.Type()))
+		return nil
+	}
+	return errors.New("cannot convert " + sv.Type().String() + " to " + fv.Type().String())
+}
+
+func toInt64(src interface{}, text string, isText bool) (int64, error) {
+	switch s := src.(type) {
+	case int64:
+		return s, nil
+	case float64:
+		return int64(s), nil
+	case bool:
+		if s {
+			return 1, nil
+		}
+		return 0, nil
+	}
+	if isText {
+		i, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			// some drivers return decimals as text, e.g. "10.00"
+			f, ferr := strconv.ParseFloat(text, 64)
+			if ferr != nil {
+				return 0, err
+			}
+			return int64(f), nil
+		}
+		return i, nil
+	}
+	return 0, fmt.Errorf("cannot convert %T to integer", src)
+}
@@ -0,0 +1,67 @@
+package sqla
+
+import (
+	"database/sql"
+	"testing"
+)
+
+type testBook struct {
+	ID            int
+	BookTitle     string
+	Author        *string
+	YearPublished int
+	Tags          []string
+	Editions      []int `sqla:"EditionList"`
+	Price         sql.NullFloat64
+}
+
+func TestSelectInto(t *testing.T) {
+	var DBType byte
+	DBType = SQLITE
+	var db *sql.DB
+	db = OpenSQLConnection(DBType, "file:scantest?mode=memory&cache=shared")
+	defer db.Close()
+	db.Exec("CREATE TABLE books (ID INTEGER PRIMARY KEY, BookTitle TEXT, Author TEXT, YearPublished INTEGER, Tags TEXT, EditionList TEXT, Price REAL);")
+
+	var args AnyTslice
+	args = args.AppendNonEmptyString("BookTitle", "Alice's Adventures in Wonderland")
+	args = args.AppendNonEmptyString("Author", "Lewis Carroll")
+	args = args.AppendInt("YearPublished", 1865)
+	args = args.AppendJSONList("Tags", []string{"fiction", "classic"})
+	args = args.AppendJSONListInt("EditionList", []int{1, 2})
+	args = args.AppendFloat64("Price", 9.99)
+	aliceID, _ := InsertObject(db, DBType, "books", args)
+	args = nil
+	args = args.AppendNonEmptyString("BookTitle", "Untitled")
+	InsertObject(db, DBType, "books", args)
+
+	var books []testBook
+	err := SelectInto(db, &books, "SELECT * FROM books ORDER BY ID")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(books) != 2 {
+		t.Fatalf("Expected:%d, received:%d", 2, len(books))
+	}
+	if books[0].Author == nil || *books[0].Author != "Lewis Carroll" || books[1].Author != nil {
+		t.Errorf("Expected:%s, received:%v, %v", "author and nil", books[0].Author, books[1].Author)
+	}
+	if len(books[0].Tags) != 2 || books[0].Tags[1] != "classic" || len(books[0].Editions) != 2 || books[1].Tags != nil {
+		t.Errorf("Expected:%s, received:%#v", "decoded JSON lists", books)
+	}
+	if !books[0].Price.Valid || books[0].Price.Float64 != 9.99 || books[1].Price.Valid {
+		t.Errorf("Expected:%s, received:%#v, %#v", "price and NULL", books[0].Price, books[1].Price)
+	}
+
+	var book testBook
+	err = GetByID(db, DBType, "books", &book, aliceID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if book.ID != aliceID || book.YearPublished != 1865 {
+		t.Errorf("Expected:%d, received:%#v", aliceID, book)
+	}
+	if err = GetByID(db, DBType, "books", &book, 999); err != sql.ErrNoRows {
+		t.Errorf("Expected:%v, received:%v", sql.ErrNoRows, err)
+	}
+}
@@ -0,0 +1,411 @@
+package sqla
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// SelectBuilder constructs SQL statements for select queries step by step. It renders the same SQL as ConstructSELECTquery,
+// which is a shortcut for the builder, and allows to add grouping and other parts without long lists of arguments.
+// Create it with NewSelectBuilder, then chain the methods and call Build and BuildCount:
+//
+//	sq, args, err := sqla.NewSelectBuilder(DBType).Select("ID, BookTitle").From("books").Where(F).OrderBy("ID", 1).Limit(20).Build()
+//
+// Methods which are not called just do not add the corresponding parts of statement.
+type SelectBuilder struct {
+	dbType       byte
+	table        string
+	columns      string
+	countColumns string
+	joins        []string
+	filter       Filter
+	orderBy      string
+	orderHow     int
+	limit        int
+	offset       int
+	paginate     bool
+	distinct     bool
+	seek         Seek
+	groupBy      string
+	having       string
+	havingArgs   []interface{}
+	subject      *Subject
+	action       PolicyAction
+}
+
+// NewSelectBuilder returns SelectBuilder for the database type (see constants). By default all columns (*) are selected and counted.
+func NewSelectBuilder(DBType byte) *SelectBuilder {
+	return &SelectBuilder{dbType: DBType, columns: "*", countColumns: "*"}
+}
+
+// From sets tableName to paste after FROM.
+func (b *SelectBuilder) From(tableName string) *SelectBuilder {
+	b.table = tableName
+	return b
+}
+
+// Select sets columns to select. Each argument may contain one or several comma-separated columns.
+func (b *SelectBuilder) Select(columns ...string) *SelectBuilder {
+	b.columns = strings.Join(columns, ", ")
+	return b
+}
+
+// Count sets columns to put as an argument for COUNT() in the statement made by BuildCount, e.g. "*" will be COUNT(*).
+func (b *SelectBuilder) Count(columns string) *SelectBuilder {
+	b.countColumns = columns
+	return b
+}
+
+// Join adds usual joins part of an SQL statement, e.g. "LEFT JOIN users ON users.ID = books.Author".
+func (b *SelectBuilder) Join(joins string) *SelectBuilder {
+	b.joins = append(b.joins, joins)
+	return b
+}
+
+// Where sets Filter to construct WHERE part of the statement. See Filter type and its methods.
+func (b *SelectBuilder) Where(F Filter) *SelectBuilder {
+	b.filter = F
+	return b
+}
+
+// OrderBy sets a column name or comma-separated column names to order result. orderHow is 0 for descending and 1 for ascending order.
+func (b *SelectBuilder) OrderBy(orderBy string, orderHow int) *SelectBuilder {
+	b.orderBy = orderBy
+	b.orderHow = orderHow
+	return b
+}
+
+// Limit sets the maximum number of rows to select. Pagination part of the statement is added only if Limit or Offset is called.
+func (b *SelectBuilder) Limit(limit int) *SelectBuilder {
+	b.limit = limit
+	b.paginate = true
+	return b
+}
+
+// Offset sets the number of rows to skip. It should be used together with Limit.
+func (b *SelectBuilder) Offset(offset int) *SelectBuilder {
+	b.offset = offset
+	b.paginate = true
+	return b
+}
+
+// Distinct defines whether you need to add DISTINCT keyword in your statement.
+func (b *SelectBuilder) Distinct(distinct bool) *SelectBuilder {
+	b.distinct = distinct
+	return b
+}
+
+// Seek sets seek method of pagination, see Seek type. The column to seek by is the one set by OrderBy.
+func (b *SelectBuilder) Seek(seek Seek) *SelectBuilder {
+	b.seek = seek
+	return b
+}
+
+// GroupBy sets comma-separated columns to put after GROUP BY.
+// If grouping is used BuildCount returns the statement to count groups, not rows.
+func (b *SelectBuilder) GroupBy(columns string) *SelectBuilder {
+	b.groupBy = columns
+	return b
+}
+
+// Having sets a condition to put after HAVING, e.g. "COUNT(*) > ?". Every ? in the condition is replaced with a placeholder (see MakeParam) for the next of args,
+// so do not use ? inside string literals of the condition. HAVING is added only together with GroupBy.
+func (b *SelectBuilder) Having(condition string, args ...interface{}) *SelectBuilder {
+	b.having = condition
+	b.havingArgs = args
+	return b
+}
+
+// Permit makes the statements include only the rows the subject may use for the action according to the table Policy (see TableMeta).
+// It overrides Filter.Subject, which permits ActionRead.
+func (b *SelectBuilder) Permit(subject Subject, action PolicyAction) *SelectBuilder {
+	b.subject = &subject
+	b.action = action
+	return b
+}
+
+// Build returns SQL statement for select query and arguments slice to use in Go sql functions.
+func (b *SelectBuilder) Build() (sq string, args []interface{}, err error) {
+	if b.table == "" {
+		return "", nil, errors.New(currentFunction() + ": table is not set")
+	}
+	if err = b.validate(); err != nil {
+		return "", nil, fmt.Errorf("%s: %w", currentFunction(), err)
+	}
+	var argstoAppend []interface{}
+	argsCounter, where, args := b.where()
+	meta := GetTableMeta(b.table)
+
+	if b.distinct {
+		sq = "SELECT DISTINCT " + b.columns + " FROM " + b.table + " " + strings.Join(b.joins, " ") + " " + where
+	} else {
+		sq = "SELECT " + b.columns + " FROM " + b.table + " " + strings.Join(b.joins, " ") + " " + where
+	}
+
+	if b.seek.UseSeek {
+		operator := " > "
+		if b.orderHow == 0 && !b.seek.ValueInclude {
+			operator = " < "
+		} else if b.orderHow == 0 && b.seek.ValueInclude {
+			operator = " <= "
+		} else if b.orderHow == 1 && b.seek.ValueInclude {
+			operator = " >= "
+		}
+		argsCounter, sq, argstoAppend = buildSQLCOMPARE(b.dbType, sq, argsCounter, meta.quote(b.dbType, b.orderBy), operator, b.seek.Value)
+		args = append(args, argstoAppend...)
+	}
+
+	argsCounter, sq, argstoAppend = b.grouping(argsCounter, sq)
+	args = append(args, argstoAppend...)
+
+	var ordarr []string
+	ordarr = strings.Split(b.orderBy, ",")
+
+	if len(b.orderBy) > 0 {
+		sq += "ORDER BY "
+		for i, col := range ordarr {
+			if meta.QuoteNames {
+				col = meta.quote(b.dbType, strings.TrimSpace(col))
+			}
+			sq += col
+			sq += GetDialect(b.dbType).OrderCollation()
+			if b.orderHow == 0 {
+				sq += " DESC "
+			} else {
+				sq += " ASC "
+			}
+			if (i + 1) < len(ordarr) {
+				sq += ", "
+			}
+		}
+	}
+
+	if b.paginate {
+		var pagination string
+		pagination, argstoAppend = GetDialect(b.dbType).Pagination(b.limit, b.offset)
+		argsCounter, pagination = bindMarkers(b.dbType, argsCounter, pagination)
+		sq += pagination
+		args = append(args, argstoAppend...)
+	}
+
+	if DEBUG {
+		log.Println(sq, args)
+	}
+	return sq, args, nil
+}
+
+// BuildCount returns SQL statement for COUNT() with the same filters as the statement made by Build, and arguments slice to use in Go sql functions.
+// Seek, order and pagination are not applied to it.
+func (b *SelectBuilder) BuildCount() (sqcount string, argscount []interface{}, err error) {
+	if b.table == "" {
+		return "", nil, errors.New(currentFunction() + ": table is not set")
+	}
+	if err = b.validate(); err != nil {
+		return "", nil, fmt.Errorf("%s: %w", currentFunction(), err)
+	}
+	argsCounter, where, args := b.where()
+	argscount = make([]interface{}, len(args))
+	copy(argscount, args)
+
+	if b.groupBy != "" {
+		var argstoAppend []interface{}
+		sqcount = "SELECT " + b.groupBy + " FROM " + b.table + " " + strings.Join(b.joins, " ") + " " + where
+		argsCounter, sqcount, argstoAppend = b.grouping(argsCounter, sqcount)
+		argscount = append(argscount, argstoAppend...)
+		sqcount = "SELECT COUNT(*) FROM (" + sqcount + ") sqlagroups"
+	} else if b.distinct {
+		sqcount = "SELECT COUNT(DISTINCT " + b.countColumns + ") FROM " + b.table + " " + strings.Join(b.joins, " ") + " " + where
+	} else {
+		sqcount = "SELECT COUNT(" + b.countColumns + ") FROM " + b.table + " " + strings.Join(b.joins, " ") + " " + where
+	}
+
+	if DEBUG {
+		log.Println(sqcount, argscount)
+	}
+	return sqcount, argscount, nil
+}
+
+// validate checks the columns of Filter and orderBy, see TableMeta.Columns.
+func (b *SelectBuilder) validate() error {
+	if err := b.filter.Validate(b.table); err != nil {
+		return err
+	}
+	if b.orderBy == "" {
+		return nil
+	}
+	meta := GetTableMeta(b.table)
+	for _, col := range strings.Split(b.orderBy, ",") {
+		if err := meta.checkColumn(strings.TrimSpace(col)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// where makes WHERE part of the statement based on Filter.
+func (b *SelectBuilder) where() (argsCounter int, sq string, args []interface{}) {
+
+	var argstoAppend []interface{}
+	DBType := b.dbType
+	meta := GetTableMeta(b.table)
+	F := b.filter
+	if meta.QuoteNames {
+		F = F.mapColumns(func(col string) string { return meta.quote(DBType, col) })
+	}
+
+	if meta.SoftDeleteColumn != "" && !F.IncludeDeleted {
+		argsCounter, sq, argstoAppend = meta.buildNotDeleted(DBType, sq, argsCounter)
+		args = append(args, argstoAppend...)
+	}
+	if b.subject != nil {
+		argsCounter, sq, argstoAppend = meta.buildPolicy(DBType, sq, argsCounter, *b.subject, b.action)
+		args = append(args, argstoAppend...)
+	} else if F.Subject != nil {
+		argsCounter, sq, argstoAppend = meta.buildPolicy(DBType, sq, argsCounter, *F.Subject, ActionRead)
+		args = append(args, argstoAppend...)
+	}
+
+	for _, FC := range F.ClassFilter {
+		argsCounter, sq, argstoAppend = buildClassFilter(DBType, sq, argsCounter, FC)
+		args = append(args, argstoAppend...)
+	}
+
+	var CurrentFCName string
+	if len(F.ClassFilterOR) > 0 {
+		CurrentFCName = F.ClassFilterOR[0].Name
+	}
+	for i, FC := range F.ClassFilterOR {
+		FirstIter := false
+		LastIter := false
+		if i == 0 {
+			FirstIter = true
+		}
+		if i == len(F.ClassFilterOR)-1 {
+			LastIter = true
+		}
+		if i+1 < len(F.ClassFilterOR) {
+			if FC.Name != F.ClassFilterOR[i+1].Name {
+				LastIter = true
+			}
+		}
+		if CurrentFCName != FC.Name {
+			FirstIter = true
+			CurrentFCName = FC.Name
+		}
+		if FC.InJSON {
+			argsCounter, sq, argstoAppend = buildSQLINJSONListOR(DBType, sq, argsCounter, FC.Column, FC.List, FirstIter, LastIter)
+			args = append(args, argstoAppend...)
+		} else {
+			argsCounter, sq, argstoAppend = BuildSQLINOR(DBType, sq, argsCounter, FC.Column, FC.List, FirstIter, LastIter)
+			args = append(args, argstoAppend...)
+		}
+	}
+
+	for _, FC := range F.StringClassFilter {
+		argsCounter, sq, argstoAppend = buildStringClassFilter(DBType, sq, argsCounter, FC)
+		args = append(args, argstoAppend...)
+	}
+
+	for i, FC := range F.StringClassFilterOR {
+		FirstIter := i == 0 || F.StringClassFilterOR[i-1].Name != FC.Name
+		LastIter := i == len(F.StringClassFilterOR)-1 || F.StringClassFilterOR[i+1].Name != FC.Name
+		argsCounter, sq, argstoAppend = buildStringClassFilterOR(DBType, sq, argsCounter, FC, FirstIter, LastIter)
+		args = append(args, argstoAppend...)
+	}
+
+	for _, NF := range F.NullFilter {
+		argsCounter, sq, argstoAppend = buildNullFilter(DBType, sq, argsCounter, NF)
+		args = append(args, argstoAppend...)
+	}
+
+	for _, BF := range F.BoolFilter {
+		argsCounter, sq, argstoAppend = buildBoolFilter(DBType, sq, argsCounter, BF)
+		args = append(args, argstoAppend...)
+	}
+
+	for _, DF := range F.DateFilter {
+		argsCounter, sq, argstoAppend = buildDateFilter(DBType, sq, argsCounter, DF)
+		args = append(args, argstoAppend...)
+	}
+
+	for _, SF := range F.SumFilter {
+		argsCounter, sq, argstoAppend = buildSumFilter(DBType, sq, argsCounter, SF)
+		args = append(args, argstoAppend...)
+	}
+
+	argsCounter, sq, argstoAppend = buildTextFilter(DBType, sq, argsCounter, F.TextFilter, F.TextFilterColumns)
+	args = append(args, argstoAppend...)
+
+	if F.Predicate != nil {
+		argsCounter, sq, argstoAppend = F.Predicate.build(DBType, sq, argsCounter)
+		args = append(args, argstoAppend...)
+	}
+
+	return argsCounter, sq, args
+}
+
+// buildClassFilter adds to sq condition of ClassFilter.
+func buildClassFilter(DBType byte, sq string, argsCounter int, FC ClassFilter) (counter int, resquery string, args []interface{}) {
+	if FC.InJSON {
+		return buildSQLINJSONList(DBType, sq, argsCounter, FC.Column, FC.List)
+	}
+	return BuildSQLIN(DBType, sq, argsCounter, FC.Column, FC.List)
+}
+
+// buildDateFilter adds to sq condition of DateFilter, nothing is added if there are no dates.
+func buildDateFilter(DBType byte, sq string, argsCounter int, DF DateFilter) (counter int, resquery string, args []interface{}) {
+	if len(DF.Dates) == 0 {
+		return argsCounter, sq, nil
+	}
+	if len(DF.Dates) == 1 {
+		return buildSQLCOMPARE(DBType, sq, argsCounter, DF.Column, getRelationFromString(DF.Relation), DF.Dates[0])
+	}
+	return buildSQLstrBETWEEN(DBType, sq, argsCounter, DF.Column, DF.Dates)
+}
+
+// buildSumFilter adds to sq condition of SumFilter, nothing is added if there are no sums.
+func buildSumFilter(DBType byte, sq string, argsCounter int, SF SumFilter) (counter int, resquery string, args []interface{}) {
+	if len(SF.Sums) == 0 {
+		return argsCounter, sq, nil
+	}
+	var argstoAppend []interface{}
+	if len(SF.Sums) == 1 {
+		argsCounter, sq, args = buildSQLCOMPARE(DBType, sq, argsCounter, SF.Column, getRelationFromString(SF.Relation), SF.Sums[0])
+	} else {
+		argsCounter, sq, args = buildSQLintBETWEEN(DBType, sq, argsCounter, SF.Column, SF.Sums)
+	}
+	if SF.CurrencyCode != 0 {
+		argsCounter, sq, argstoAppend = buildSQLCOMPARE(DBType, sq, argsCounter, SF.CurrencyColumn, " = ", SF.CurrencyCode)
+		args = append(args, argstoAppend...)
+	}
+	return argsCounter, sq, args
+}
+
+// buildTextFilter adds to sq condition to search for text in columns, nothing is added if text is empty.
+func buildTextFilter(DBType byte, sq string, argsCounter int, text string, columns []string) (counter int, resquery string, args []interface{}) {
+	if text == "" {
+		return argsCounter, sq, nil
+	}
+	operator, caseins := GetDialect(DBType).TextSearch(text)
+	if !GetDialect(DBType).NumberedPlaceholders() {
+		// MySQL, Oracle, and others with ? or unaccessible by number placeholder:
+		return buildUncountedSQLTXTSearch(DBType, sq, argsCounter, operator, text, caseins, columns)
+	}
+	return buildSQLTXTSearch(DBType, sq, argsCounter, operator, text, caseins, columns)
+}
+
+// grouping adds GROUP BY and HAVING parts to the statement.
+func (b *SelectBuilder) grouping(argsCounter int, sq string) (counter int, resquery string, args []interface{}) {
+	if b.groupBy != "" {
+		sq += "GROUP BY " + b.groupBy + " "
+		if b.having != "" {
+			var having string
+			argsCounter, having = bindMarkers(b.dbType, argsCounter, b.having)
+			sq += "HAVING " + having + " "
+			args = append(args, b.havingArgs...)
+		}
+	}
+	return argsCounter, sq, args
+}
@@ -0,0 +1,83 @@
+package sqla
+
+import (
+	"reflect"
+	"testing"
+)
+
+func testFilter() Filter {
+	return Filter{
+		ClassFilter:       []ClassFilter{{Name: "doctypes", Column: "DocType", List: []int{1, 2}}, {Name: "tags", InJSON: true, Column: "Tags", List: []int{3}}},
+		ClassFilterOR:     []ClassFilter{{Name: "people", Column: "Creator", List: []int{7, 8}}, {Name: "people", Column: "Assignee", List: []int{7, 8}}},
+		DateFilter:        []DateFilter{{Name: "created", Column: "Created", Relation: "<>", Dates: []int64{100}}},
+		SumFilter:         []SumFilter{{Name: "sums", Column: "Sum", CurrencyColumn: "Currency", CurrencyCode: 840, Sums: []int{0, 1000}}},
+		TextFilter:        "Тест",
+		TextFilterColumns: []string{"About", "Note"},
+	}
+}
+
+func TestConstructSELECTquery(t *testing.T) {
+	tests := []struct {
+		DBType  byte
+		sq      string
+		sqcount string
+	}{
+		{SQLITE, "SELECT DISTINCT ID, RegNo FROM documents LEFT JOIN users ON users.ID = documents.Creator WHERE DocType IN ($1, $2) AND (Tags LIKE $3 OR Tags LIKE $4 OR Tags LIKE $5 OR Tags LIKE $6) AND (Creator IN ($7, $8) OR Assignee IN ($9, $10) ) AND (Created IS NULL OR Created <> $11) AND Sum BETWEEN $12 AND $13 AND Currency = $14 AND ((About LIKE $15 OR About LIKE $16 OR About LIKE $17 OR About LIKE $18) OR (Note LIKE $15 OR Note LIKE $16 OR Note LIKE $17 OR Note LIKE $18) ) AND RegDate,ID > $19 ORDER BY RegDate COLLATE NOCASE ASC , ID COLLATE NOCASE ASC  LIMIT $20 OFFSET $21",
+			"SELECT COUNT(DISTINCT ID) FROM documents LEFT JOIN users ON users.ID = documents.Creator WHERE DocType IN ($1, $2) AND (Tags LIKE $3 OR Tags LIKE $4 OR Tags LIKE $5 OR Tags LIKE $6) AND (Creator IN ($7, $8) OR Assignee IN ($9, $10) ) AND (Created IS NULL OR Created <> $11) AND Sum BETWEEN $12 AND $13 AND Currency = $14 AND ((About LIKE $15 OR About LIKE $16 OR About LIKE $17 OR About LIKE $18) OR (Note LIKE $15 OR Note LIKE $16 OR Note LIKE $17 OR Note LIKE $18) ) "},
+		{MSSQL, "SELECT DISTINCT ID, RegNo FROM documents LEFT JOIN users ON users.ID = documents.Creator WHERE DocType IN (@p1, @p2) AND (Tags LIKE @p3 OR Tags LIKE @p4 OR Tags LIKE @p5 OR Tags LIKE @p6) AND (Creator IN (@p7, @p8) OR Assignee IN (@p9, @p10) ) AND (Created IS NULL OR Created <> @p11) AND Sum BETWEEN @p12 AND @p13 AND Currency = @p14 AND (About LIKE @p15 OR Note LIKE @p15 ) AND RegDate,ID > @p16 ORDER BY RegDate ASC , ID ASC  OFFSET @p17 ROWS FETCH NEXT @p18 ROWS ONLY",
+			"SELECT COUNT(DISTINCT ID) FROM documents LEFT JOIN users ON users.ID = documents.Creator WHERE DocType IN (@p1, @p2) AND (Tags LIKE @p3 OR Tags LIKE @p4 OR Tags LIKE @p5 OR Tags LIKE @p6) AND (Creator IN (@p7, @p8) OR Assignee IN (@p9, @p10) ) AND (Created IS NULL OR Created <> @p11) AND Sum BETWEEN @p12 AND @p13 AND Currency = @p14 AND (About LIKE @p15 OR Note LIKE @p15 ) "},
+		{MYSQL, "SELECT DISTINCT ID, RegNo FROM documents LEFT JOIN users ON users.ID = documents.Creator WHERE DocType IN (?, ?) AND (Tags LIKE ? OR Tags LIKE ? OR Tags LIKE ? OR Tags LIKE ?) AND (Creator IN (?, ?) OR Assignee IN (?, ?) ) AND (Created IS NULL OR Created <> ?) AND Sum BETWEEN ? AND ? AND Currency = ? AND (About LIKE ? OR Note LIKE ? ) AND RegDate,ID > ? ORDER BY RegDate ASC , ID ASC  LIMIT ? OFFSET ?",
+			"SELECT COUNT(DISTINCT ID) FROM documents LEFT JOIN users ON users.ID = documents.Creator WHERE DocType IN (?, ?) AND (Tags LIKE ? OR Tags LIKE ? OR Tags LIKE ? OR Tags LIKE ?) AND (Creator IN (?, ?) OR Assignee IN (?, ?) ) AND (Created IS NULL OR Created <> ?) AND Sum BETWEEN ? AND ? AND Currency = ? AND (About LIKE ? OR Note LIKE ? ) "},
+		{ORACLE, "SELECT DISTINCT ID, RegNo FROM documents LEFT JOIN users ON users.ID = documents.Creator WHERE DocType IN (:1, :2) AND (Tags LIKE :3 OR Tags LIKE :4 OR Tags LIKE :5 OR Tags LIKE :6) AND (Creator IN (:7, :8) OR Assignee IN (:9, :10) ) AND (Created IS NULL OR Created <> :11) AND Sum BETWEEN :12 AND :13 AND Currency = :14 AND (About LIKE :15 COLLATE binary_ai OR Note LIKE :16 COLLATE binary_ai ) AND RegDate,ID > :17 ORDER BY RegDate ASC , ID ASC  OFFSET :18 ROWS FETCH NEXT :19 ROWS ONLY",
+			"SELECT COUNT(DISTINCT ID) FROM documents LEFT JOIN users ON users.ID = documents.Creator WHERE DocType IN (:1, :2) AND (Tags LIKE :3 OR Tags LIKE :4 OR Tags LIKE :5 OR Tags LIKE :6) AND (Creator IN (:7, :8) OR Assignee IN (:9, :10) ) AND (Created IS NULL OR Created <> :11) AND Sum BETWEEN :12 AND :13 AND Currency = :14 AND (About LIKE :15 COLLATE binary_ai OR Note LIKE :16 COLLATE binary_ai ) "},
+		{POSTGRESQL, "SELECT DISTINCT ID, RegNo FROM documents LEFT JOIN users ON users.ID = documents.Creator WHERE DocType IN ($1, $2) AND (Tags LIKE $3 OR Tags LIKE $4 OR Tags LIKE $5 OR Tags LIKE $6) AND (Creator IN ($7, $8) OR Assignee IN ($9, $10) ) AND (Created IS NULL OR Created <> $11) AND Sum BETWEEN $12 AND $13 AND Currency = $14 AND (About ILIKE $15 OR Note ILIKE $15 ) AND RegDate,ID > $16 ORDER BY RegDate ASC , ID ASC  LIMIT $17 OFFSET $18",
+			"SELECT COUNT(DISTINCT ID) FROM documents LEFT JOIN users ON users.ID = documents.Creator WHERE DocType IN ($1, $2) AND (Tags LIKE $3 OR Tags LIKE $4 OR Tags LIKE $5 OR Tags LIKE $6) AND (Creator IN ($7, $8) OR Assignee IN ($9, $10) ) AND (Created IS NULL OR Created <> $11) AND Sum BETWEEN $12 AND $13 AND Currency = $14 AND (About ILIKE $15 OR Note ILIKE $15 ) "},
+	}
+	for _, tt := range tests {
+		sq, sqcount, args, argscount := ConstructSELECTquery(tt.DBType, "documents", "ID, RegNo", "ID", "LEFT JOIN users ON users.ID = documents.Creator",
+			testFilter(), "RegDate,ID", 1, 20, 40, true, Seek{UseSeek: true, Value: 5})
+		if sq != tt.sq {
+			t.Errorf("Expected:%s, received:%s", tt.sq, sq)
+		}
+		if sqcount != tt.sqcount {
+			t.Errorf("Expected:%s, received:%s", tt.sqcount, sqcount)
+		}
+		if len(args) != len(argscount)+3 {
+			t.Errorf("Expected:%d, received:%d", len(argscount)+3, len(args))
+		}
+	}
+}
+
+func TestSelectBuilderGroupBy(t *testing.T) {
+	F := Filter{ClassFilter: []ClassFilter{{Name: "doctypes", Column: "DocType", List: []int{1, 2}}}}
+	b := NewSelectBuilder(POSTGRESQL).Select("Creator", "COUNT(*)").From("documents").Where(F).
+		GroupBy("Creator").Having("COUNT(*) > ?", 10).OrderBy("Creator", 1).Limit(20)
+
+	sq, args, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "SELECT Creator, COUNT(*) FROM documents  WHERE DocType IN ($1, $2) GROUP BY Creator HAVING COUNT(*) > $3 ORDER BY Creator ASC  LIMIT $4 OFFSET $5"
+	if sq != expected {
+		t.Errorf("Expected:%s, received:%s", expected, sq)
+	}
+	if !reflect.DeepEqual(args, []interface{}{1, 2, 10, 20, 0}) {
+		t.Errorf("Expected:%v, received:%v", []interface{}{1, 2, 10, 20, 0}, args)
+	}
+
+	sqcount, argscount, err := b.BuildCount()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = "SELECT COUNT(*) FROM (SELECT Creator FROM documents  WHERE DocType IN ($1, $2) GROUP BY Creator HAVING COUNT(*) > $3 ) sqlagroups"
+	if sqcount != expected {
+		t.Errorf("Expected:%s, received:%s", expected, sqcount)
+	}
+	if len(argscount) != 3 {
+		t.Errorf("Expected:%d, received:%d", 3, len(argscount))
+	}
+
+	if _, _, err = NewSelectBuilder(SQLITE).Build(); err == nil {
+		t.Errorf("Expected an error when table is not set, received nil")
+	}
+}
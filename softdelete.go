@@ -0,0 +1,111 @@
+package sqla
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// ErrNoSoftDelete is returned by RestoreObjects and PurgeDeleted if soft delete is not enabled for the table.
+var ErrNoSoftDelete = errors.New("soft delete is not enabled for the table")
+
+// RestoreObjects undeletes soft-deleted objects which key is present in keys list, see TableMeta.SoftDeleteColumn.
+// The key column is 'ID' unless other is registered with RegisterTable. Objects which are not deleted are not changed and not counted in rowsaff.
+// Any error is only logged, use RestoreObjectsContext to receive it.
+func RestoreObjects[K comparable](db Executor, DBType byte, table string, keys []K) (rowsaff int) {
+	rowsaff, err := RestoreObjectsContext(context.Background(), db, DBType, table, keys)
+	if err != nil {
+		log.Println(err)
+	}
+	return rowsaff
+}
+
+// RestoreObjectsContext does the same as RestoreObjects, but it takes a context and returns an error instead of logging it.
+func RestoreObjectsContext[K comparable](ctx context.Context, db Executor, DBType byte, table string, keys []K) (rowsaff int, err error) {
+	if err = ValidateColumns(table); err != nil {
+		return 0, fmt.Errorf("%s: %w", currentFunction(), err)
+	}
+	meta := GetTableMeta(table)
+	if meta.SoftDeleteColumn == "" {
+		return 0, wrapError(currentFunction(), ErrNoSoftDelete)
+	}
+	keyColumn, err := meta.keyColumn()
+	if err != nil {
+		return 0, wrapError(currentFunction(), err)
+	}
+	if len(keys) == 0 {
+		return 0, nil
+	}
+	if ok, err := auditInTx(ctx, db, meta, func(tx *sql.Tx) (err error) {
+		rowsaff, err = RestoreObjectsContext(ctx, tx, DBType, table, keys)
+		return err
+	}); ok {
+		return rowsaff, err
+	}
+	var sq = "UPDATE " + table + " SET " + meta.SoftDeleteColumn + " = "
+	var args []interface{}
+	var argsCounter int
+	after := map[string]interface{}{meta.SoftDeleteColumn: nil}
+	if meta.SoftDeleteType == SoftDeleteFlag {
+		argsCounter++
+		sq += MakeParam(DBType, argsCounter) + " "
+		args = append(args, BoolValue(DBType, false))
+		after[meta.SoftDeleteColumn] = false
+	} else {
+		sq += "NULL "
+	}
+	for _, chunk := range chunkValues(DBType, keys, argsCounter+1) {
+		ra, err := meta.execAudited(ctx, db, DBType, currentFunction(), AuditUpdate, argsCounter, sq, args[:argsCounter:argsCounter], after, func(sq string, argsCounter int) (int, string, []interface{}, error) {
+			argsCounter, sq, args := BuildSQLINOf(DBType, sq, argsCounter, keyColumn, chunk)
+			argsCounter, sq, argstoAppend := meta.buildDeleted(DBType, sq, argsCounter)
+			return argsCounter, sq, append(args, argstoAppend...), nil
+		})
+		rowsaff += ra
+		if err != nil {
+			return rowsaff, err
+		}
+	}
+	return rowsaff, nil
+}
+
+// PurgeDeleted physically removes soft-deleted rows of the table, see TableMeta.SoftDeleteColumn.
+// For SoftDeleteTimestamp only the rows deleted before the specified time are removed, so it may be used to clear rows after retention period,
+// for SoftDeleteFlag all deleted rows are removed. Any error is only logged, use PurgeDeletedContext to receive it.
+func PurgeDeleted(db Executor, DBType byte, table string, before time.Time) (rowsaff int) {
+	rowsaff, err := PurgeDeletedContext(context.Background(), db, DBType, table, before)
+	if err != nil {
+		log.Println(err)
+	}
+	return rowsaff
+}
+
+// PurgeDeletedContext does the same as PurgeDeleted, but it takes a context and returns an error instead of logging it.
+func PurgeDeletedContext(ctx context.Context, db Executor, DBType byte, table string, before time.Time) (rowsaff int, err error) {
+	if err = ValidateColumns(table); err != nil {
+		return 0, fmt.Errorf("%s: %w", currentFunction(), err)
+	}
+	meta := GetTableMeta(table)
+	if meta.SoftDeleteColumn == "" {
+		return 0, wrapError(currentFunction(), ErrNoSoftDelete)
+	}
+	if ok, err := auditInTx(ctx, db, meta, func(tx *sql.Tx) (err error) {
+		rowsaff, err = PurgeDeletedContext(ctx, tx, DBType, table, before)
+		return err
+	}); ok {
+		return rowsaff, err
+	}
+	data := &HookData{Table: table, Column: meta.SoftDeleteColumn}
+	return deleteWithHooks(ctx, currentFunction(), data, func() (int, error) {
+		return meta.execAudited(ctx, db, DBType, currentFunction(), AuditDelete, 0, "DELETE FROM "+table+" ", nil, nil, func(sq string, argsCounter int) (int, string, []interface{}, error) {
+			if meta.SoftDeleteType == SoftDeleteFlag {
+				argsCounter, sq, args := buildSQLCOMPARE(DBType, sq, argsCounter, meta.SoftDeleteColumn, " = ", BoolValue(DBType, true))
+				return argsCounter, sq, args, nil
+			}
+			argsCounter, sq, args := buildSQLCOMPARE(DBType, sq, argsCounter, meta.SoftDeleteColumn, " < ", before.UnixNano())
+			return argsCounter, sq, args, nil
+		})
+	})
+}
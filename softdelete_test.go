@@ -0,0 +1,82 @@
+package sqla
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestSoftDelete(t *testing.T) {
+	var DBType byte
+	DBType = SQLITE
+	var db *sql.DB
+	db = OpenSQLConnection(DBType, "file:softdeletetest?mode=memory&cache=shared")
+	defer db.Close()
+	db.Exec("CREATE TABLE letters (ID INTEGER PRIMARY KEY, Subject TEXT, DeletedAt INTEGER);")
+	db.Exec("CREATE TABLE memos (ID INTEGER PRIMARY KEY, Subject TEXT, Deleted INTEGER NOT NULL DEFAULT 0);")
+	RegisterTable(TableMeta{Name: "letters", SoftDeleteColumn: "DeletedAt"})
+	RegisterTable(TableMeta{Name: "memos", SoftDeleteColumn: "Deleted", SoftDeleteType: SoftDeleteFlag})
+
+	count := func(table string, F Filter) (n int) {
+		sq, args, err := NewSelectBuilder(DBType).From(table).Where(F).BuildCount()
+		if err != nil {
+			t.Fatal(err)
+		}
+		db.QueryRow(sq, args...).Scan(&n)
+		return n
+	}
+
+	for _, table := range []string{"letters", "memos"} {
+		var ids []int
+		for i := 0; i < 3; i++ {
+			var args AnyTslice
+			args = args.AppendNonEmptyString("Subject", "hello")
+			ID, _ := InsertObject(db, DBType, table, args)
+			ids = append(ids, ID)
+		}
+		if rowsaff := DeleteObjects(db, DBType, table, "ID", ids[:2]); rowsaff != 2 {
+			t.Errorf("Expected:%d, received:%d", 2, rowsaff)
+		}
+		if rowsaff := DeleteObject(db, DBType, table, "ID", ids[0]); rowsaff != 0 {
+			t.Errorf("Expected:%d, received:%d", 0, rowsaff)
+		}
+		if n := count(table, Filter{}); n != 1 {
+			t.Errorf("Expected:%d, received:%d", 1, n)
+		}
+		if n := count(table, Filter{IncludeDeleted: true}); n != 3 {
+			t.Errorf("Expected:%d, received:%d", 3, n)
+		}
+		var obj struct{ Subject string }
+		if err := GetByID(db, DBType, table, &obj, ids[0]); err != sql.ErrNoRows {
+			t.Errorf("Expected:%v, received:%v", sql.ErrNoRows, err)
+		}
+		if err := GetByID(db, DBType, table, &obj, ids[2]); err != nil || obj.Subject != "hello" {
+			t.Errorf("Expected:%s, received:%s, %v", "hello", obj.Subject, err)
+		}
+		if rowsaff := RestoreObjects(db, DBType, table, ids[1:3]); rowsaff != 1 {
+			t.Errorf("Expected:%d, received:%d", 1, rowsaff)
+		}
+		var purged int
+		RegisterHooks(table, Hooks{AfterDelete: func(ctx context.Context, data *HookData) error {
+			purged = data.RowsAffected
+			return nil
+		}})
+		if rowsaff := PurgeDeleted(db, DBType, table, time.Now().Add(time.Second)); rowsaff != 1 || purged != 1 {
+			t.Errorf("Expected:%d, received:%d, %d", 1, rowsaff, purged)
+		}
+		ResetHooks(table)
+		if n := count(table, Filter{IncludeDeleted: true}); n != 2 {
+			t.Errorf("Expected:%d, received:%d", 2, n)
+		}
+	}
+
+	sq, _, _ := NewSelectBuilder(DBType).From("letters").Join("LEFT JOIN memos ON memos.ID = letters.ID").Build()
+	if expected := "SELECT * FROM letters LEFT JOIN memos ON memos.ID = letters.ID WHERE letters.DeletedAt IS NULL "; sq != expected {
+		t.Errorf("Expected:%s, received:%s", expected, sq)
+	}
+
+	if rowsaff := PurgeDeleted(db, DBType, "books", time.Now()); rowsaff != 0 {
+		t.Errorf("Expected:%d, received:%d", 0, rowsaff)
+	}
+}
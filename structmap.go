@@ -0,0 +1,187 @@
+package sqla
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// fieldInfo describes how a struct field is mapped to a table column.
+type fieldInfo struct {
+	column    string
+	index     []int
+	omitEmpty bool
+	nullEmpty bool
+	json      bool
+}
+
+var fieldsCache sync.Map // map[reflect.Type][]fieldInfo
+
+// structFields returns the mapping of struct fields to table columns.
+// Every exported field is mapped to a column named after the field unless the `sqla` tag sets other name or "-" to skip the field.
+// Fields of embedded structs without a tag are mapped as if they were fields of the outer struct.
+func structFields(t reflect.Type) []fieldInfo {
+	if cached, ok := fieldsCache.Load(t); ok {
+		return cached.([]fieldInfo)
+	}
+	var fields []fieldInfo
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag, tagged := sf.Tag.Lookup("sqla")
+		if tag == "-" {
+			continue
+		}
+		if sf.Anonymous && !tagged && sf.Type.Kind() == reflect.Struct {
+			for _, f := range structFields(sf.Type) {
+				f.index = append([]int{i}, f.index...)
+				fields = append(fields, f)
+			}
+			continue
+		}
+		if sf.PkgPath != "" {
+			continue // unexported
+		}
+		f := fieldInfo{column: sf.Name, index: []int{i}}
+		opts := strings.Split(tag, ",")
+		if opts[0] != "" {
+			f.column = opts[0]
+		}
+		for _, opt := range opts[1:] {
+			switch opt {
+			case "omitempty":
+				f.omitEmpty = true
+			case "nullempty":
+				f.nullEmpty = true
+			case "json":
+				f.json = true
+			}
+		}
+		fields = append(fields, f)
+	}
+	fieldsCache.Store(t, fields)
+	return fields
+}
+
+// StructToAnyTslice makes AnyTslice from a struct (or a pointer to a struct) to use it with InsertObject or UpdateObject.
+// Columns are defined by field names or by `sqla` tags, e.g. `sqla:"AppName,omitempty"`. Tag "-" skips a field.
+// Tag options have the same meaning as the corresponding AnyTslice methods:
+// omitempty - a zero value is not appended at all (like AppendNonEmptyString);
+// nullempty - a zero value is appended as nil (like AppendStringOrNil);
+// json - a value is appended as JSON: []string like AppendJSONList, []int like AppendJSONListInt, other types like AppendJSONStruct.
+// Nil pointers are appended as nil. Types implementing driver.Valuer (e.g. sql.NullString) are appended by their values.
+//
+// If skipID is true the 'ID' column is not appended, as it is usually generated by database on insert and passed separately on update.
+// Use StructToAnyTsliceFor to skip the key columns registered for a table with RegisterTable.
+func StructToAnyTslice(obj interface{}, skipID bool) (AnyTslice, error) {
+	var skip []string
+	if skipID {
+		skip = []string{"ID"}
+	}
+	a, err := structToAnyTslice(obj, skip)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", currentFunction(), err)
+	}
+	return a, nil
+}
+
+// StructToAnyTsliceFor does the same as StructToAnyTslice, but if skipKey is true it does not append the key columns of the table
+// ('ID' unless other is registered with RegisterTable), so a column named 'ID' which is not the key is appended.
+func StructToAnyTsliceFor(table string, obj interface{}, skipKey bool) (AnyTslice, error) {
+	var skip []string
+	if skipKey {
+		skip = GetTableMeta(table).Key
+	}
+	a, err := structToAnyTslice(obj, skip)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", currentFunction(), err)
+	}
+	return a, nil
+}
+
+// structToAnyTslice makes AnyTslice from a struct skipping the columns listed in skip.
+func structToAnyTslice(obj interface{}, skip []string) (AnyTslice, error) {
+	v := reflect.ValueOf(obj)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, fmt.Errorf("nil pointer")
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected struct, got %s", v.Kind())
+	}
+	var a AnyTslice
+	var err error
+	for _, f := range structFields(v.Type()) {
+		if containsFold(skip, f.column) {
+			continue
+		}
+		a, err = a.appendValue(f, v.FieldByIndex(f.index))
+		if err != nil {
+			return nil, fmt.Errorf("field for column %s: %w", f.column, err)
+		}
+	}
+	return a, nil
+}
+
+var valuerType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+
+// appendValue appends field value fv according to the field mapping options.
+func (a AnyTslice) appendValue(f fieldInfo, fv reflect.Value) (AnyTslice, error) {
+	if f.omitEmpty && fv.IsZero() {
+		return a, nil
+	}
+	if f.json {
+		if fv.Kind() == reflect.Slice {
+			if fv.Len() == 0 {
+				return a.AppendNil(f.column), nil
+			}
+			switch list := fv.Interface().(type) {
+			case []string:
+				return a.AppendJSONList(f.column, list), nil
+			case []int:
+				return a.AppendJSONListInt(f.column, list), nil
+			}
+		}
+		if (fv.Kind() == reflect.Ptr || fv.Kind() == reflect.Map) && fv.IsNil() {
+			return a.AppendNil(f.column), nil
+		}
+		return a.AppendJSONStruct(f.column, fv.Interface()), nil
+	}
+	if f.nullEmpty && fv.IsZero() {
+		return a.AppendNil(f.column), nil
+	}
+	if fv.Type().Implements(valuerType) {
+		if fv.Kind() == reflect.Ptr && fv.IsNil() {
+			return a.AppendNil(f.column), nil
+		}
+		val, err := fv.Interface().(driver.Valuer).Value()
+		if err != nil {
+			return a, err
+		}
+		if val == nil {
+			return a.AppendNil(f.column), nil
+		}
+		fv = reflect.ValueOf(val)
+	}
+	switch fv.Kind() {
+	case reflect.Ptr:
+		if fv.IsNil() {
+			return a.AppendNil(f.column), nil
+		}
+		return a.appendValue(fieldInfo{column: f.column}, fv.Elem())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.AppendInt64(f.column, fv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return a.AppendInt64(f.column, int64(fv.Uint())), nil
+	case reflect.Bool:
+		return a.AppendBool(f.column, fv.Bool()), nil
+	case reflect.Float32, reflect.Float64:
+		return a.AppendFloat64(f.column, fv.Float()), nil
+	case reflect.String:
+		return a.AppendString(f.column, fv.String()), nil
+	}
+	return a, fmt.Errorf("unsupported type %s, use json tag option", fv.Type())
+}
@@ -0,0 +1,67 @@
+package sqla
+
+import (
+	"database/sql"
+	"testing"
+)
+
+type testTimestamps struct {
+	Created  int64
+	Modified int64 `sqla:",omitempty"`
+}
+
+type testApp struct {
+	ID           int
+	AppName      string   `sqla:"AppName,omitempty"`
+	Author       string   `sqla:",nullempty"`
+	Tags         []string `sqla:",json"`
+	Versions     []int    `sqla:",json"`
+	Rating       sql.NullFloat64
+	Homepage     *string
+	YearReleased int
+	Internal     string `sqla:"-"`
+	testTimestamps
+}
+
+func TestStructToAnyTslice(t *testing.T) {
+	app := testApp{ID: 5, AppName: "Linux", Tags: []string{"os"}, YearReleased: 1991, Internal: "x"}
+	app.Created = 100
+	args, err := StructToAnyTslice(&app, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var expected AnyTslice
+	expected = expected.AppendNonEmptyString("AppName", app.AppName)
+	expected = expected.AppendStringOrNil("Author", app.Author)
+	expected = expected.AppendJSONList("Tags", app.Tags)
+	expected = expected.AppendJSONListInt("Versions", app.Versions)
+	expected = expected.AppendNil("Rating")
+	expected = expected.AppendNil("Homepage")
+	expected = expected.AppendInt("YearReleased", app.YearReleased)
+	expected = expected.AppendInt64("Created", app.Created)
+
+	if len(args) != len(expected) {
+		t.Fatalf("Expected:%#v, received:%#v", expected, args)
+	}
+	for i := range expected {
+		if args[i] != expected[i] {
+			t.Errorf("Expected:%#v, received:%#v", expected[i], args[i])
+		}
+	}
+
+	args, _ = StructToAnyTslice(app, false)
+	if args[0].c != "ID" || args[0].i != 5 {
+		t.Errorf("Expected:%s, received:%#v", "ID column first", args[0])
+	}
+
+	RegisterTable(TableMeta{Name: "catalog_apps", Key: []string{"AppName"}, KeyType: KeyString})
+	args, _ = StructToAnyTsliceFor("catalog_apps", app, true)
+	if len(args) != len(expected) || args[0].c != "ID" || args[1].c != "Author" {
+		t.Errorf("Expected:%s, received:%#v", "ID column without AppName", args)
+	}
+
+	if _, err = StructToAnyTslice(struct{ Ch chan int }{}, false); err == nil {
+		t.Errorf("Expected an error on unsupported type, received nil")
+	}
+}
@@ -0,0 +1,207 @@
+package sqla
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// KeyType defines the type of primary key values of a table.
+type KeyType byte
+
+// KeyInt64 - integer keys, usually generated by database (default);
+// KeyString - text keys;
+// KeyUUID - UUID keys, stored as text or in native UUID type of a database.
+const (
+	KeyInt64 KeyType = iota
+	KeyString
+	KeyUUID
+)
+
+// VersionType defines how the version column of a table is changed on update.
+type VersionType byte
+
+// VersionCounter - integer column incremented by 1 (default);
+// VersionTimestamp - integer column set to the current Unix time in nanoseconds.
+const (
+	VersionCounter VersionType = iota
+	VersionTimestamp
+)
+
+// SoftDeleteType defines how rows are marked as deleted if soft delete is enabled for a table.
+type SoftDeleteType byte
+
+// SoftDeleteTimestamp - integer column set to the Unix time of deletion in nanoseconds, NULL for rows which are not deleted (default);
+// SoftDeleteFlag - boolean column set to true for deleted rows, it should be false (not NULL) for other rows.
+const (
+	SoftDeleteTimestamp SoftDeleteType = iota
+	SoftDeleteFlag
+)
+
+// RelationAction defines what DeleteWithDependents does with rows referencing deleted ones.
+type RelationAction byte
+
+// OnDeleteCascade - referencing rows are deleted too (default);
+// OnDeleteSetNull - referencing column is set to NULL;
+// OnDeleteRestrict - deletion fails with *RestrictError if there are referencing rows.
+const (
+	OnDeleteCascade RelationAction = iota
+	OnDeleteSetNull
+	OnDeleteRestrict
+)
+
+// Relation describes a child table which Column references the key of a table, see TableMeta.Relations.
+type Relation struct {
+	Table  string
+	Column string
+	Action RelationAction
+}
+
+// TableMeta describes a table for the functions of this package.
+// Name is the table name as it is passed to the functions. Key contains primary key column(s), if empty the key is 'ID' column.
+// KeyType is the type of key values. Functions which take or return int IDs may be used only with single-column KeyInt64 keys.
+//
+// VersionColumn enables optimistic locking in UpdateObject and UpdateObjectByKey if not empty. The column is changed on every update according to VersionType.
+// The value of VersionColumn read with the object should be present in the updated values, it is not set but compared with the value in the table,
+// and ErrStaleObject is returned if they differ, i.e. the object was changed by someone else after it was read. ErrNoVersion is returned if the value is absent.
+// UpdateSingleInt and similar functions cannot pass the version, so they return ErrNoVersion for such tables. Other update functions do not change the version column.
+//
+// SoftDeleteColumn enables soft delete if not empty: delete functions mark rows as deleted according to SoftDeleteType instead of removing them,
+// select statements made by SelectBuilder and ConstructSELECTquery exclude such rows unless Filter.IncludeDeleted is true, and GetByID does not return them.
+// See RestoreObjects and PurgeDeleted to undelete rows and to remove them physically.
+//
+// Relations lists child tables referencing rows of the table, they are processed by DeleteWithDependents.
+//
+// Audit enables writing audit records (see AuditTable) by InsertObject, InsertObjects, UpdateObject, UpsertObject, UpdateMultipleWithOneInt, SetToNull, delete functions,
+// RestoreObjects and PurgeDeleted with all their variants. The records are written in the same transaction as the changes, if db is *sql.DB a transaction is started for that.
+// CopyIn cannot write audit records, it returns an error wrapping ErrAuditNotSupported for such tables.
+//
+// Policy defines row-level permissions, see VerifyPermissions, SelectBuilder.Permit and Filter.Subject.
+//
+// Columns is an allow-list of column names (compared case-insensitively). If it is not empty, columns of Filter and orderBy of select statements,
+// columns of values passed to insert, update and upsert functions, and column arguments of delete, SetToNull and UpdateMultipleWithOneInt functions
+// are checked against it, and an error wrapping ErrUnknownColumn is returned for other columns.
+// These columns and table names passed to the functions of this package are always checked to be valid identifiers (see ValidIdentifier),
+// an error wrapping ErrInvalidIdentifier is returned otherwise. The table (which may have an alias), selected columns and joins passed to SelectBuilder
+// and ConstructSELECTquery are not checked, they should not come from a client.
+//
+// QuoteNames makes the checked columns quoted for the database (see QuoteIdentifier) in the statements: columns of Filter, orderBy and Seek,
+// and columns of values passed to insert, update and upsert functions. Table names, key, version and soft delete columns are not quoted.
+// Quoted names are case-sensitive in some databases (e.g. PostgreSQL and Oracle), so the columns should have the case they have in the database.
+type TableMeta struct {
+	Name             string
+	Key              []string
+	KeyType          KeyType
+	VersionColumn    string
+	VersionType      VersionType
+	SoftDeleteColumn string
+	SoftDeleteType   SoftDeleteType
+	Relations        []Relation
+	Audit            bool
+	Policy           *Policy
+	Columns          []string
+	QuoteNames       bool
+}
+
+var (
+	tablesMu sync.RWMutex
+	tables   = map[string]TableMeta{}
+)
+
+// ErrCompositeKey is returned by functions which take a single ID if the table has composite primary key.
+var ErrCompositeKey = errors.New("table has composite key")
+
+// ErrStaleObject is returned by update functions if the object was changed or deleted after it was read, see TableMeta.VersionColumn.
+var ErrStaleObject = errors.New("object was changed or deleted by someone else")
+
+// ErrNoVersion is returned by UpdateObject and UpdateObjectByKey if the table has version column, but its value is not present in the updated values.
+var ErrNoVersion = errors.New("version of the object is not specified")
+
+// RegisterTable registers metadata for a table, replacing metadata registered before if any.
+// Tables which are not registered have 'ID' integer key column.
+func RegisterTable(meta TableMeta) {
+	if len(meta.Key) == 0 {
+		meta.Key = []string{"ID"}
+	}
+	tablesMu.Lock()
+	defer tablesMu.Unlock()
+	tables[meta.Name] = meta
+}
+
+// GetTableMeta returns metadata registered for the table, or default metadata if the table is not registered.
+func GetTableMeta(table string) TableMeta {
+	tablesMu.RLock()
+	meta, ok := tables[table]
+	tablesMu.RUnlock()
+	if !ok {
+		return TableMeta{Name: table, Key: []string{"ID"}, KeyType: KeyInt64}
+	}
+	return meta
+}
+
+// keyColumn returns the key column of the table, or ErrCompositeKey if the key consists of several columns.
+func (m TableMeta) keyColumn() (string, error) {
+	if len(m.Key) != 1 {
+		return "", ErrCompositeKey
+	}
+	return m.Key[0], nil
+}
+
+// buildKeyWhere adds to sq 'WHERE/AND key_column1 = $1 AND key_column2 = $2' with as many values in key as there are key columns.
+func (m TableMeta) buildKeyWhere(DBType byte, sq string, argsCounter int, key []interface{}) (counter int, resquery string, args []interface{}, err error) {
+	if len(key) != len(m.Key) {
+		return argsCounter, sq, nil, errors.New("table " + m.Name + " key (" + strings.Join(m.Key, ", ") + ") does not match the number of key values")
+	}
+	for i := range m.Key {
+		if i == 0 && !strings.Contains(sq, "WHERE") {
+			sq += "WHERE "
+		} else {
+			sq += "AND "
+		}
+		argsCounter++
+		sq += m.Key[i] + " = " + MakeParam(DBType, argsCounter) + " "
+		args = append(args, key[i])
+	}
+	return argsCounter, sq, args, nil
+}
+
+// softDeleteSet returns 'UPDATE table SET column = $1 ' statement to mark rows as deleted, and the argument for it.
+func (m TableMeta) softDeleteSet(DBType byte) (sq string, args []interface{}) {
+	sq = "UPDATE " + m.Name + " SET " + m.SoftDeleteColumn + " = " + MakeParam(DBType, 1) + " "
+	if m.SoftDeleteType == SoftDeleteFlag {
+		return sq, []interface{}{BoolValue(DBType, true)}
+	}
+	return sq, []interface{}{time.Now().UnixNano()}
+}
+
+// buildDeleted adds to sq 'WHERE/AND table.column IS NOT NULL' or 'WHERE/AND table.column = true' condition to select only soft-deleted rows, it is the inverse of buildNotDeleted.
+func (m TableMeta) buildDeleted(DBType byte, sq string, argsCounter int) (counter int, resquery string, args []interface{}) {
+	column := m.Name + "." + m.SoftDeleteColumn
+	if m.SoftDeleteType == SoftDeleteFlag {
+		return buildSQLCOMPARE(DBType, sq, argsCounter, column, " = ", BoolValue(DBType, true))
+	}
+	if strings.Contains(sq, "WHERE") {
+		sq += "AND "
+	} else {
+		sq += "WHERE "
+	}
+	sq += column + " IS NOT NULL "
+	return argsCounter, sq, nil
+}
+
+// buildNotDeleted adds to sq 'WHERE/AND table.column IS NULL' or 'WHERE/AND table.column = false' condition to exclude soft-deleted rows.
+// The column is qualified with the table name, so the condition is not ambiguous if other tables are joined.
+func (m TableMeta) buildNotDeleted(DBType byte, sq string, argsCounter int) (counter int, resquery string, args []interface{}) {
+	column := m.Name + "." + m.SoftDeleteColumn
+	if m.SoftDeleteType == SoftDeleteFlag {
+		return buildSQLCOMPARE(DBType, sq, argsCounter, column, " = ", BoolValue(DBType, false))
+	}
+	if strings.Contains(sq, "WHERE") {
+		sq += "AND "
+	} else {
+		sq += "WHERE "
+	}
+	sq += column + " IS NULL "
+	return argsCounter, sq, nil
+}
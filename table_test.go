@@ -0,0 +1,98 @@
+package sqla
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+func TestTableKeys(t *testing.T) {
+	var DBType byte
+	DBType = SQLITE
+	var db *sql.DB
+	db = OpenSQLConnection(DBType, "file:tabletest?mode=memory&cache=shared")
+	defer db.Close()
+	db.Exec("CREATE TABLE legacy_users (user_id INTEGER PRIMARY KEY, Name TEXT);")
+	db.Exec("CREATE TABLE memberships (UserID INTEGER, GroupID INTEGER, Role TEXT, PRIMARY KEY (UserID, GroupID));")
+	RegisterTable(TableMeta{Name: "legacy_users", Key: []string{"user_id"}})
+	RegisterTable(TableMeta{Name: "memberships", Key: []string{"UserID", "GroupID"}})
+
+	var args AnyTslice
+	args = args.AppendNonEmptyString("Name", "alice")
+	userID, _ := InsertObject(db, DBType, "legacy_users", args)
+	args = nil
+	args = args.AppendNonEmptyString("Name", "bob")
+	rowsaff, err := UpdateObjectContext(context.Background(), db, DBType, "legacy_users", args, userID)
+	if err != nil || rowsaff != 1 {
+		t.Errorf("Expected:%d, received:%d, %v", 1, rowsaff, err)
+	}
+	if rowsaff = SetToNullOneByID(db, DBType, "legacy_users", "Name", userID); rowsaff != 1 {
+		t.Errorf("Expected:%d, received:%d", 1, rowsaff)
+	}
+
+	args = nil
+	args = args.AppendInt("UserID", userID)
+	args = args.AppendInt("GroupID", 2)
+	args = args.AppendNonEmptyString("Role", "member")
+	lastid, rowsaff := InsertObject(db, DBType, "memberships", args)
+	if lastid != 0 || rowsaff != 1 {
+		t.Errorf("Expected:%d, %d, received:%d, %d", 0, 1, lastid, rowsaff)
+	}
+	args = nil
+	args = args.AppendNonEmptyString("Role", "admin")
+	if rowsaff = UpdateObjectByKey(db, DBType, "memberships", args, userID, 2); rowsaff != 1 {
+		t.Errorf("Expected:%d, received:%d", 1, rowsaff)
+	}
+	if _, err = UpdateObjectContext(context.Background(), db, DBType, "memberships", args, userID); err == nil {
+		t.Errorf("Expected an error on key mismatch, received nil")
+	}
+	if _, err = SetToNullOneByIDContext(context.Background(), db, DBType, "memberships", "Role", userID); !errors.Is(err, ErrCompositeKey) {
+		t.Errorf("Expected:%v, received:%v", ErrCompositeKey, err)
+	}
+	if rowsaff = DeleteObjectByKey(db, DBType, "memberships", userID, 2); rowsaff != 1 {
+		t.Errorf("Expected:%d, received:%d", 1, rowsaff)
+	}
+}
+
+func TestStringKeys(t *testing.T) {
+	var DBType byte
+	DBType = SQLITE
+	var db *sql.DB
+	db = OpenSQLConnection(DBType, "file:stringkeystest?mode=memory&cache=shared")
+	defer db.Close()
+	db.Exec("CREATE TABLE events (ID TEXT PRIMARY KEY, Name TEXT);")
+	RegisterTable(TableMeta{Name: "events", KeyType: KeyUUID})
+
+	ids := []string{"0190a3f0-0000-7000-8000-000000000001", "0190a3f0-0000-7000-8000-000000000002"}
+	for _, id := range ids {
+		var args AnyTslice
+		args = args.AppendNonEmptyString("ID", id)
+		args = args.AppendNonEmptyString("Name", "event")
+		var key string
+		rowsaff, err := InsertObjectKeyContext(context.Background(), db, DBType, "events", args, &key)
+		if err != nil || rowsaff != 1 || key != id {
+			t.Errorf("Expected:%s, %d, received:%s, %d, %v", id, 1, key, rowsaff, err)
+		}
+	}
+	var args AnyTslice
+	args = args.AppendNonEmptyString("Name", "renamed")
+	if rowsaff := UpdateObjectByKey(db, DBType, "events", args, ids[0]); rowsaff != 1 {
+		t.Errorf("Expected:%d, received:%d", 1, rowsaff)
+	}
+	var key string
+	if _, err := InsertObjectKeyContext(context.Background(), db, DBType, "events", args, &key); err == nil {
+		t.Errorf("Expected an error when key is not returned by database, received nil")
+	}
+
+	sq := "SELECT * FROM events "
+	_, sq, inargs := BuildSQLINOf(POSTGRESQL, sq, 0, "ID", ids)
+	expected := "SELECT * FROM events WHERE ID IN ($1, $2) "
+	if sq != expected || len(inargs) != 2 || inargs[1] != ids[1] {
+		t.Errorf("Expected:%s, received:%s, %v", expected, sq, inargs)
+	}
+
+	if rowsaff := DeleteObjectsByKeys(db, DBType, "events", ids); rowsaff != 2 {
+		t.Errorf("Expected:%d, received:%d", 2, rowsaff)
+	}
+}
@@ -0,0 +1,69 @@
+package sqla
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+)
+
+// Executor is implemented by *sql.DB, *sql.Tx and *sql.Conn. Write helpers of this package accept an Executor,
+// so they can be executed either directly on a database or within a transaction (see WithTx).
+type Executor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// TxRetries is the number of times WithTx and WithTxContext run a transaction function again
+// if the transaction failed due to serialization failure or deadlock.
+var TxRetries = 3
+
+// WithTx begins a transaction and passes it to fn. If fn returns nil the transaction is committed, otherwise it is rolled back.
+// The whole transaction is retried (see TxRetries) if it failed due to a serialization failure or a deadlock (see ErrSerialization and ErrDeadlock),
+// so fn should not have side effects outside the database.
+func WithTx(db *sql.DB, fn func(tx *sql.Tx) error) error {
+	return WithTxContext(context.Background(), db, nil, fn)
+}
+
+// WithTxContext does the same as WithTx, but it takes a context and transaction options (opts may be nil).
+func WithTxContext(ctx context.Context, db *sql.DB, opts *sql.TxOptions, fn func(tx *sql.Tx) error) (err error) {
+	for attempt := 0; ; attempt++ {
+		err = runTx(ctx, db, opts, fn)
+		if err == nil || attempt >= TxRetries || !isRetryableTxError(err) || ctx.Err() != nil {
+			return err
+		}
+		if DEBUG {
+			log.Println(currentFunction()+": retrying transaction:", err)
+		}
+	}
+}
+
+func runTx(ctx context.Context, db *sql.DB, opts *sql.TxOptions, fn func(tx *sql.Tx) error) (err error) {
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		return wrapError(currentFunction(), err)
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+	if err = fn(tx); err != nil {
+		if rberr := tx.Rollback(); rberr != nil && !errors.Is(rberr, sql.ErrTxDone) {
+			log.Println(currentFunction()+":", rberr)
+		}
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return wrapError(currentFunction(), err)
+	}
+	return nil
+}
+
+// isRetryableTxError reports if err is a serialization failure or a deadlock.
+func isRetryableTxError(err error) bool {
+	err = ClassifyError(err)
+	return errors.Is(err, ErrSerialization) || errors.Is(err, ErrDeadlock)
+}
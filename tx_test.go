@@ -0,0 +1,52 @@
+package sqla
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+func TestWithTx(t *testing.T) {
+	var DBType byte
+	DBType = SQLITE
+	var db *sql.DB
+	db = OpenSQLConnection(DBType, "file:txtest?mode=memory&cache=shared")
+	defer db.Close()
+	db.Exec("CREATE TABLE parents (ID INTEGER PRIMARY KEY, Name TEXT);")
+	db.Exec("CREATE TABLE children (ID INTEGER PRIMARY KEY, Parent INTEGER, Name TEXT);")
+
+	errAbort := errors.New("abort")
+	err := WithTx(db, func(tx *sql.Tx) error {
+		var args AnyTslice
+		args = args.AppendNonEmptyString("Name", "parent")
+		parentID, _ := InsertObject(tx, DBType, "parents", args)
+		args = nil
+		args = args.AppendInt("Parent", parentID)
+		args = args.AppendNonEmptyString("Name", "child")
+		InsertObject(tx, DBType, "children", args)
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Errorf("Expected:%v, received:%v", errAbort, err)
+	}
+	var count int
+	db.QueryRow("SELECT COUNT(*) FROM parents").Scan(&count)
+	if count != 0 {
+		t.Errorf("Expected:%d, received:%d", 0, count)
+	}
+
+	err = WithTx(db, func(tx *sql.Tx) error {
+		var args AnyTslice
+		args = args.AppendNonEmptyString("Name", "parent")
+		_, _, err := InsertObjectContext(context.Background(), tx, DBType, "parents", args)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.QueryRow("SELECT COUNT(*) FROM parents").Scan(&count)
+	if count != 1 {
+		t.Errorf("Expected:%d, received:%d", 1, count)
+	}
+}
@@ -1,13 +1,43 @@
 package sqla
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
 	"log"
+	"time"
 )
 
 // UpdateObject creates an SQL statement and executes it to update an object in the specified table.
-// The function returns the number of affected rows. Update will be done on the object where column 'ID' contains ID value.
-func UpdateObject(db *sql.DB, DBType byte, table string, iargs []anyT, ID int) (rowsaff int) {
+// The function returns the number of affected rows. Update will be done on the object where key column ('ID' unless other is registered with RegisterTable) contains ID value.
+// If the table has version column registered with RegisterTable, see TableMeta for optimistic locking.
+// Any error is only logged, use UpdateObjectContext to receive it.
+func UpdateObject(db Executor, DBType byte, table string, iargs []anyT, ID int) (rowsaff int) {
+	rowsaff, err := UpdateObjectContext(context.Background(), db, DBType, table, iargs, ID)
+	if err != nil {
+		log.Println(err)
+	}
+	return rowsaff
+}
+
+// UpdateObjectContext does the same as UpdateObject, but it takes a context to cancel the query or to set a timeout for it,
+// and returns an error instead of logging it. The returned error wraps the one returned by the database driver.
+func UpdateObjectContext(ctx context.Context, db Executor, DBType byte, table string, iargs []anyT, ID int) (rowsaff int, err error) {
+	return UpdateObjectByKeyContext(ctx, db, DBType, table, iargs, ID)
+}
+
+// UpdateObjectByKey does the same as UpdateObject, but the object is defined by key values of any type, one value for each key column registered with RegisterTable.
+// It should be used for tables with composite or non-integer keys. Any error is only logged, use UpdateObjectByKeyContext to receive it.
+func UpdateObjectByKey(db Executor, DBType byte, table string, iargs []anyT, key ...interface{}) (rowsaff int) {
+	rowsaff, err := UpdateObjectByKeyContext(context.Background(), db, DBType, table, iargs, key...)
+	if err != nil {
+		log.Println(err)
+	}
+	return rowsaff
+}
+
+// UpdateObjectByKeyContext does the same as UpdateObjectByKey, but it takes a context and returns an error instead of logging it.
+func UpdateObjectByKeyContext(ctx context.Context, db Executor, DBType byte, table string, iargs []anyT, key ...interface{}) (rowsaff int, err error) {
 
 	const (
 		I = 0
@@ -17,15 +47,38 @@ func UpdateObject(db *sql.DB, DBType byte, table string, iargs []anyT, ID int) (
 		N = 4
 	)
 
+	meta := GetTableMeta(table)
+	if ok, err := auditInTx(ctx, db, meta, func(tx *sql.Tx) (err error) {
+		rowsaff, err = UpdateObjectByKeyContext(ctx, tx, DBType, table, iargs, key...)
+		return err
+	}); ok {
+		return rowsaff, err
+	}
+	data := &HookData{Table: table, Args: iargs, Keys: key}
+	if err = runHooks(ctx, beforeUpdate, data); err != nil {
+		return 0, fmt.Errorf("%s: %w", currentFunction(), err)
+	}
+	iargs = data.Args
+	if err = meta.checkArgs(iargs); err != nil {
+		return 0, fmt.Errorf("%s: %w", currentFunction(), err)
+	}
+
+	var version interface{}
+	var versioned bool
 	var colvalpairs string
 	var counter int
-	var args []interface{}
+	var args, argstoAppend []interface{}
 	for j := 0; j < len(iargs); j++ {
+		if meta.VersionColumn != "" && iargs[j].c == meta.VersionColumn {
+			version = argValue(iargs[j])
+			versioned = true
+			continue
+		}
 		counter++
-		if j > 0 {
+		if colvalpairs != "" {
 			colvalpairs += ", "
 		}
-		colvalpairs += iargs[j].c + " = " + MakeParam(DBType, counter)
+		colvalpairs += meta.quote(DBType, iargs[j].c) + " = " + MakeParam(DBType, counter)
 		switch iargs[j].t {
 		case I:
 			args = append(args, iargs[j].i)
@@ -39,115 +92,200 @@ func UpdateObject(db *sql.DB, DBType byte, table string, iargs []anyT, ID int) (
 			args = append(args, nil)
 		}
 	}
-	counter++
-	args = append(args, ID)
-	sq := "UPDATE " + table + " SET " + colvalpairs + " WHERE ID = " + MakeParam(DBType, counter)
-
-	if DEBUG {
-		log.Println(sq, args)
+	if meta.VersionColumn != "" {
+		if !versioned {
+			return 0, fmt.Errorf("%s: %w", currentFunction(), ErrNoVersion)
+		}
+		if colvalpairs != "" {
+			colvalpairs += ", "
+		}
+		if meta.VersionType == VersionTimestamp {
+			counter++
+			colvalpairs += meta.VersionColumn + " = " + MakeParam(DBType, counter)
+			args = append(args, time.Now().UnixNano())
+		} else {
+			colvalpairs += meta.VersionColumn + " = " + meta.VersionColumn + " + 1"
+		}
 	}
-	res, err := db.Exec(sq, args...)
+	sq := "UPDATE " + table + " SET " + colvalpairs + " "
+	counter, sq, argstoAppend, err = meta.buildKeyWhere(DBType, sq, counter, key)
 	if err != nil {
-		log.Println(currentFunction()+":", err)
-		return
+		return 0, wrapError(currentFunction(), err)
+	}
+	args = append(args, argstoAppend...)
+	if versioned {
+		counter++
+		sq += "AND " + meta.VersionColumn + " = " + MakeParam(DBType, counter) + " "
+		args = append(args, version)
+	}
+
+	var before []map[string]interface{}
+	if meta.Audit {
+		_, selsq, selargs, _ := meta.buildKeyWhere(DBType, "SELECT * FROM "+table+" ", 0, key)
+		if before, err = readRows(ctx, db, selsq, selargs); err != nil {
+			return 0, wrapError(currentFunction(), err)
+		}
+	}
+
+	rowsaff, err = execRowsAffected(ctx, db, currentFunction(), sq, args)
+	if err == nil && versioned && rowsaff == 0 {
+		return 0, fmt.Errorf("%s: %w", currentFunction(), ErrStaleObject)
 	}
-	ra, err := res.RowsAffected()
 	if err != nil {
-		log.Println(currentFunction()+":", err)
+		return rowsaff, err
+	}
+	if meta.Audit && rowsaff > 0 {
+		if err = writeAuditRows(ctx, db, DBType, meta, AuditUpdate, before, anyTMap(iargs)); err != nil {
+			return 0, wrapError(currentFunction(), err)
+		}
 	}
-	rowsaff = int(ra)
+	data.RowsAffected = rowsaff
+	if err = runHooks(ctx, afterUpdate, data); err != nil {
+		return rowsaff, fmt.Errorf("%s: %w", currentFunction(), err)
+	}
+	return rowsaff, nil
 
-	return rowsaff
+}
 
+// UpdateMultipleWithOneInt updates with val the column of an object which id is present in ids list and in key column ('ID' unless other is registered with RegisterTable). Rows which already have val in the column will not be updated.
+// If ids list exceeds the number of parameters allowed by the database, several statements are executed, so use a transaction (see WithTx) if they should be atomic. If necessary you can provide timestamp and a column for timestamp; if you don't need to update any timestamp column use empty string as the argument for that column.
+// Any error is only logged, use UpdateMultipleWithOneIntContext to receive it.
+func UpdateMultipleWithOneInt(db Executor, DBType byte, table string, column string, val int, timecol string, timestamp int64, ids []int) (rowsaff int) {
+	rowsaff, err := UpdateMultipleWithOneIntContext(context.Background(), db, DBType, table, column, val, timecol, timestamp, ids)
+	if err != nil {
+		log.Println(err)
+	}
+	return rowsaff
 }
 
-// UpdateMultipleWithOneInt updates with val the column of an object which id is present in ids list and in 'ID' column. Rows which already have val in the column will not be updated. If necessary you can provide timestamp and a column for timestamp; if you don't need to update any timestamp column use empty string as the argument for that column.
-func UpdateMultipleWithOneInt(db *sql.DB, DBType byte, table string, column string, val int, timecol string, timestamp int64, ids []int) (rowsaff int) {
+// UpdateMultipleWithOneIntContext does the same as UpdateMultipleWithOneInt, but it takes a context and returns an error instead of logging it.
+func UpdateMultipleWithOneIntContext(ctx context.Context, db Executor, DBType byte, table string, column string, val int, timecol string, timestamp int64, ids []int) (rowsaff int, err error) {
+	columns := []string{column}
+	if timecol != "" {
+		columns = append(columns, timecol)
+	}
+	if err = ValidateColumns(table, columns...); err != nil {
+		return 0, fmt.Errorf("%s: %w", currentFunction(), err)
+	}
+	meta := GetTableMeta(table)
+	keyColumn, err := meta.keyColumn()
+	if err != nil {
+		return 0, wrapError(currentFunction(), err)
+	}
+	if len(ids) == 0 {
+		return rowsaff, nil
+	}
+	if ok, err := auditInTx(ctx, db, meta, func(tx *sql.Tx) (err error) {
+		rowsaff, err = UpdateMultipleWithOneIntContext(ctx, tx, DBType, table, column, val, timecol, timestamp, ids)
+		return err
+	}); ok {
+		return rowsaff, err
+	}
 	var sq = "UPDATE " + table + " SET " + column + " = " + MakeParam(DBType, 1) + " "
-	var args, argstoAppend []interface{}
+	var args []interface{}
 	args = append(args, val)
 	var argsCounter = 1
-	var res sql.Result
-	var err error
+	after := map[string]interface{}{column: val}
 
 	if timecol != "" {
 		argsCounter++
 		sq += ", " + timecol + " = " + MakeParam(DBType, argsCounter) + " "
 		args = append(args, timestamp)
+		after[timecol] = timestamp
 	}
 
-	argsCounter++
-	sq += "WHERE " + column + " <> " + MakeParam(DBType, argsCounter) + " "
-	args = append(args, val)
-
-	if len(ids) > 0 {
-		argsCounter, sq, argstoAppend = BuildSQLIN(DBType, sq, argsCounter, "ID", ids)
-		args = append(args, argstoAppend...)
-		if DEBUG {
-			log.Println(sq, args)
-		}
-		res, err = db.Exec(sq, args...)
+	for _, chunk := range chunkValues(DBType, ids, argsCounter+1) {
+		ra, err := meta.execAudited(ctx, db, DBType, currentFunction(), AuditUpdate, argsCounter, sq, args[:argsCounter:argsCounter], after, func(sq string, argsCounter int) (int, string, []interface{}, error) {
+			argsCounter++
+			sq += "WHERE " + column + " <> " + MakeParam(DBType, argsCounter) + " "
+			argsCounter, sq, argstoAppend := BuildSQLIN(DBType, sq, argsCounter, keyColumn, chunk)
+			return argsCounter, sq, append([]interface{}{val}, argstoAppend...), nil
+		})
+		rowsaff += ra
 		if err != nil {
-			log.Println(currentFunction()+":", err)
+			return rowsaff, err
 		}
-		ra, err := res.RowsAffected()
-		if err != nil {
-			log.Println(currentFunction()+":", err)
-		}
-		rowsaff = int(ra)
 	}
-	return rowsaff
+	return rowsaff, nil
 }
 
 // SetToNull sets to NULL the column of any object which has a value from a list in that column.
-func SetToNull(db *sql.DB, DBType byte, table string, column string, list []int) (rowsaff int) {
+// Any error is only logged, use SetToNullContext to receive it.
+func SetToNull(db Executor, DBType byte, table string, column string, list []int) (rowsaff int) {
+	rowsaff, err := SetToNullContext(context.Background(), db, DBType, table, column, list)
+	if err != nil {
+		log.Println(err)
+	}
+	return rowsaff
+}
+
+// SetToNullContext does the same as SetToNull, but it takes a context and returns an error instead of logging it.
+func SetToNullContext(ctx context.Context, db Executor, DBType byte, table string, column string, list []int) (rowsaff int, err error) {
+	if err = ValidateColumns(table, column); err != nil {
+		return 0, fmt.Errorf("%s: %w", currentFunction(), err)
+	}
 
+	if len(list) == 0 {
+		return rowsaff, nil
+	}
+	meta := GetTableMeta(table)
+	if ok, err := auditInTx(ctx, db, meta, func(tx *sql.Tx) (err error) {
+		rowsaff, err = SetToNullContext(ctx, tx, DBType, table, column, list)
+		return err
+	}); ok {
+		return rowsaff, err
+	}
 	var sq = "UPDATE " + table + " SET " + column + " = NULL "
-	var args, argstoAppend []interface{}
-	var argsCounter int
-	var res sql.Result
-	var err error
-
-	if len(list) > 0 {
-		argsCounter, sq, argstoAppend = BuildSQLIN(DBType, sq, argsCounter, column, list)
-		args = append(args, argstoAppend...)
-		if DEBUG {
-			log.Println(sq, args)
-		}
-		res, err = db.Exec(sq, args...)
+	after := map[string]interface{}{column: nil}
+	for _, chunk := range chunkValues(DBType, list, 0) {
+		ra, err := meta.execAudited(ctx, db, DBType, currentFunction(), AuditUpdate, 0, sq, nil, after, func(sq string, argsCounter int) (int, string, []interface{}, error) {
+			argsCounter, sq, args := BuildSQLIN(DBType, sq, argsCounter, column, chunk)
+			return argsCounter, sq, args, nil
+		})
+		rowsaff += ra
 		if err != nil {
-			log.Println(currentFunction()+":", err)
+			return rowsaff, err
 		}
-		ra, err := res.RowsAffected()
-		if err != nil {
-			log.Println(currentFunction()+":", err)
-		}
-		rowsaff = int(ra)
+	}
+	return rowsaff, nil
+}
+
+// SetToNullOneByID sets to NULL the column of an object which has a specified ID in key column ('ID' unless other is registered with RegisterTable).
+// Any error is only logged, use SetToNullOneByIDContext to receive it.
+func SetToNullOneByID(db Executor, dbType byte, table string, column string, id int) (rowsaff int) {
+	rowsaff, err := SetToNullOneByIDContext(context.Background(), db, dbType, table, column, id)
+	if err != nil {
+		log.Println(err)
 	}
 	return rowsaff
 }
 
-// SetToNullOneByID sets to NULL the column of an object which has a specified ID.
-func SetToNullOneByID(db *sql.DB, dbType byte, table string, column string, id int) (rowsaff int) {
-	var sq = "UPDATE " + table + " SET " + column + " = NULL WHERE ID = " + MakeParam(dbType, 1)
-	if DEBUG {
-		log.Println(sq, id)
+// SetToNullOneByIDContext does the same as SetToNullOneByID, but it takes a context and returns an error instead of logging it.
+func SetToNullOneByIDContext(ctx context.Context, db Executor, dbType byte, table string, column string, id int) (rowsaff int, err error) {
+	if err = ValidateColumns(table, column); err != nil {
+		return 0, fmt.Errorf("%s: %w", currentFunction(), err)
 	}
-	res, err := db.Exec(sq, id)
+	meta := GetTableMeta(table)
+	keyColumn, err := meta.keyColumn()
 	if err != nil {
-		log.Println(currentFunction()+":", err)
+		return 0, wrapError(currentFunction(), err)
 	}
-	ra, err := res.RowsAffected()
-	if err != nil {
-		log.Println(currentFunction()+":", err)
+	if ok, err := auditInTx(ctx, db, meta, func(tx *sql.Tx) (err error) {
+		rowsaff, err = SetToNullOneByIDContext(ctx, tx, dbType, table, column, id)
+		return err
+	}); ok {
+		return rowsaff, err
 	}
-	rowsaff = int(ra)
-	return rowsaff
+	var sq = "UPDATE " + table + " SET " + column + " = NULL "
+	return meta.execAudited(ctx, db, dbType, currentFunction(), AuditUpdate, 0, sq, nil, map[string]interface{}{column: nil}, func(sq string, argsCounter int) (int, string, []interface{}, error) {
+		argsCounter, sq, args := buildSQLCOMPARE(dbType, sq, argsCounter, keyColumn, " = ", id)
+		return argsCounter, sq, args, nil
+	})
 }
 
 // UpdateSingleInt creates an SQL statement and executes it to update only one value of an object in database.
 // It executes UpdateObject.
-func UpdateSingleInt(db *sql.DB, DBType byte, table string, column string, valueToSet int, ID int) (rowsaff int) {
+func UpdateSingleInt(db Executor, DBType byte, table string, column string, valueToSet int, ID int) (rowsaff int) {
 	var args AnyTslice
 	args = args.AppendInt(column, valueToSet)
 	rowsaff = UpdateObject(db, DBType, table, args, ID)
@@ -156,7 +294,7 @@ func UpdateSingleInt(db *sql.DB, DBType byte, table string, column string, value
 
 // UpdateSingleStr creates an SQL statement and executes it to update only one value of an object in database.
 // It executes UpdateObject.
-func UpdateSingleStr(db *sql.DB, DBType byte, table string, column string, valueToSet string, ID int) (rowsaff int) {
+func UpdateSingleStr(db Executor, DBType byte, table string, column string, valueToSet string, ID int) (rowsaff int) {
 	var args AnyTslice
 	args = args.AppendStringOrNil(column, valueToSet)
 	rowsaff = UpdateObject(db, DBType, table, args, ID)
@@ -165,7 +303,7 @@ func UpdateSingleStr(db *sql.DB, DBType byte, table string, column string, value
 
 // UpdateSingleJSONStruct creates an SQL statement and executes it to update only one value of an object in database.
 // It executes UpdateObject.
-func UpdateSingleJSONStruct(db *sql.DB, DBType byte, table string, column string, valueToSet interface{}, ID int) (rowsaff int) {
+func UpdateSingleJSONStruct(db Executor, DBType byte, table string, column string, valueToSet interface{}, ID int) (rowsaff int) {
 	var args AnyTslice
 	args = args.AppendJSONStruct(column, valueToSet)
 	rowsaff = UpdateObject(db, DBType, table, args, ID)
@@ -174,7 +312,7 @@ func UpdateSingleJSONStruct(db *sql.DB, DBType byte, table string, column string
 
 // UpdateSingleJSONListStr creates an SQL statement and executes it to update only one value of an object in database.
 // It executes UpdateObject.
-func UpdateSingleJSONListStr(db *sql.DB, DBType byte, table string, column string, valueToSet []string, ID int) (rowsaff int) {
+func UpdateSingleJSONListStr(db Executor, DBType byte, table string, column string, valueToSet []string, ID int) (rowsaff int) {
 	var args AnyTslice
 	args = args.AppendJSONList(column, valueToSet)
 	rowsaff = UpdateObject(db, DBType, table, args, ID)
@@ -183,7 +321,7 @@ func UpdateSingleJSONListStr(db *sql.DB, DBType byte, table string, column strin
 
 // UpdateSingleJSONListInt creates an SQL statement and executes it to update only one value of an object in database.
 // It executes UpdateObject.
-func UpdateSingleJSONListInt(db *sql.DB, DBType byte, table string, column string, valueToSet []int, ID int) (rowsaff int) {
+func UpdateSingleJSONListInt(db Executor, DBType byte, table string, column string, valueToSet []int, ID int) (rowsaff int) {
 	var args AnyTslice
 	args = args.AppendJSONListInt(column, valueToSet)
 	rowsaff = UpdateObject(db, DBType, table, args, ID)
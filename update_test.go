@@ -1,7 +1,9 @@
 package sqla
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"testing"
 )
 
@@ -26,3 +28,52 @@ func TestUpdateObject(t *testing.T) {
 	UpdateObject(db, DBType, "documents", testobj, ID)
 	db.Close()
 }
+
+func TestUpdateObjectVersion(t *testing.T) {
+	var DBType byte
+	DBType = SQLITE
+	var db *sql.DB
+	db = OpenSQLConnection(DBType, "file:versiontest?mode=memory&cache=shared")
+	defer db.Close()
+	db.Exec("CREATE TABLE contracts (ID INTEGER PRIMARY KEY, Title TEXT, Version INTEGER NOT NULL DEFAULT 1);")
+	RegisterTable(TableMeta{Name: "contracts", VersionColumn: "Version"})
+
+	var args AnyTslice
+	args = args.AppendNonEmptyString("Title", "draft")
+	ID, _ := InsertObject(db, DBType, "contracts", args)
+
+	args = nil
+	args = args.AppendNonEmptyString("Title", "first editor")
+	args = args.AppendInt("Version", 1)
+	rowsaff, err := UpdateObjectContext(context.Background(), db, DBType, "contracts", args, ID)
+	if err != nil || rowsaff != 1 {
+		t.Errorf("Expected:%d, received:%d, %v", 1, rowsaff, err)
+	}
+	args = nil
+	args = args.AppendNonEmptyString("Title", "second editor")
+	args = args.AppendInt("Version", 1)
+	if _, err = UpdateObjectContext(context.Background(), db, DBType, "contracts", args, ID); !errors.Is(err, ErrStaleObject) {
+		t.Errorf("Expected:%v, received:%v", ErrStaleObject, err)
+	}
+	var title string
+	var version int
+	db.QueryRow("SELECT Title, Version FROM contracts WHERE ID = $1", ID).Scan(&title, &version)
+	if title != "first editor" || version != 2 {
+		t.Errorf("Expected:%s, %d, received:%s, %d", "first editor", 2, title, version)
+	}
+
+	RegisterTable(TableMeta{Name: "contracts", VersionColumn: "Version", VersionType: VersionTimestamp})
+	args = nil
+	args = args.AppendNonEmptyString("Title", "third editor")
+	if _, err = UpdateObjectContext(context.Background(), db, DBType, "contracts", args, ID); !errors.Is(err, ErrNoVersion) {
+		t.Errorf("Expected:%v, received:%v", ErrNoVersion, err)
+	}
+	args = args.AppendInt("Version", 2)
+	if rowsaff = UpdateObject(db, DBType, "contracts", args, ID); rowsaff != 1 {
+		t.Errorf("Expected:%d, received:%d", 1, rowsaff)
+	}
+	db.QueryRow("SELECT Version FROM contracts WHERE ID = $1", ID).Scan(&version)
+	if version <= 2 {
+		t.Errorf("Expected:%s, received:%d", "timestamp version", version)
+	}
+}
@@ -0,0 +1,166 @@
+package sqla
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// UpsertObject inserts an object into the specified table, or updates the existing one if it has the same values in conflictColumns, in one statement.
+// It uses ON CONFLICT clause for PostgreSQL, SQLite, CockroachDB and DuckDB, ON DUPLICATE KEY UPDATE for MySQL and MariaDB, and MERGE for MSSQL and Oracle.
+// If conflictColumns is empty the key columns of the table are used ('ID' unless other key is registered with RegisterTable), values of conflict columns should be present in iargs.
+// The conflict columns should have a unique index or constraint. MySQL ignores conflictColumns and checks all unique keys of the table.
+// updateColumns are the columns to update in the existing row, if empty all columns of iargs except conflict columns are updated.
+//
+// It returns the ID of inserted or updated record and the number of affected rows. For tables with composite or non-integer keys the returned ID is 0.
+// If audit is enabled for the table (see TableMeta.Audit), the existing row is read before the statement in a transaction to write update or insert audit record.
+// Any error is only logged, use UpsertObjectContext to receive it.
+func UpsertObject(db Executor, DBType byte, table string, iargs []anyT, conflictColumns []string, updateColumns []string) (id int, rowsaff int) {
+	id, rowsaff, err := UpsertObjectContext(context.Background(), db, DBType, table, iargs, conflictColumns, updateColumns)
+	if err != nil {
+		log.Println(err)
+	}
+	return id, rowsaff
+}
+
+// UpsertObjectContext does the same as UpsertObject, but it takes a context and returns an error instead of logging it.
+func UpsertObjectContext(ctx context.Context, db Executor, DBType byte, table string, iargs []anyT, conflictColumns []string, updateColumns []string) (id int, rowsaff int, err error) {
+	meta := GetTableMeta(table)
+	if ok, err := auditInTx(ctx, db, meta, func(tx *sql.Tx) (err error) {
+		id, rowsaff, err = UpsertObjectContext(ctx, tx, DBType, table, iargs, conflictColumns, updateColumns)
+		return err
+	}); ok {
+		return id, rowsaff, err
+	}
+	fname := currentFunction()
+	if len(conflictColumns) == 0 {
+		conflictColumns = meta.Key
+	}
+	if err = meta.checkArgs(iargs); err != nil {
+		return 0, 0, fmt.Errorf("%s: %w", currentFunction(), err)
+	}
+	for _, col := range append(conflictColumns[:len(conflictColumns):len(conflictColumns)], updateColumns...) {
+		if err = meta.checkColumn(col); err != nil {
+			return 0, 0, fmt.Errorf("%s: %w", currentFunction(), err)
+		}
+	}
+
+	var columns, values []string
+	var args []interface{}
+	index := map[string]int{}
+	for j := range iargs {
+		index[iargs[j].c] = j
+		columns = append(columns, iargs[j].c)
+		values = append(values, MakeParam(DBType, j+1))
+		args = append(args, argValue(iargs[j]))
+	}
+
+	var conflictArgs []interface{}
+	for _, col := range conflictColumns {
+		j, ok := index[col]
+		if !ok {
+			return 0, 0, fmt.Errorf("%s: value of conflict column %s is not present", currentFunction(), col)
+		}
+		conflictArgs = append(conflictArgs, args[j])
+	}
+	if len(updateColumns) == 0 {
+		for _, col := range columns {
+			if !containsString(conflictColumns, col) {
+				updateColumns = append(updateColumns, col)
+			}
+		}
+	}
+	if len(updateColumns) == 0 {
+		return 0, 0, fmt.Errorf("%s: there are no columns to update", currentFunction())
+	}
+
+	var idColumn string
+	if len(meta.Key) == 1 && meta.KeyType == KeyInt64 {
+		idColumn = meta.Key[0]
+	}
+	var where []string
+	for i, col := range conflictColumns {
+		where = append(where, meta.quote(DBType, col)+" = "+MakeParam(DBType, i+1))
+	}
+
+	if meta.Audit {
+		var before []map[string]interface{}
+		before, err = readRows(ctx, db, "SELECT * FROM "+table+" WHERE "+strings.Join(where, " AND "), conflictArgs)
+		if err != nil {
+			return 0, 0, wrapError(fname, err)
+		}
+		defer func() {
+			if err != nil || rowsaff == 0 {
+				return
+			}
+			if len(before) > 0 {
+				after := map[string]interface{}{}
+				for _, col := range updateColumns {
+					if j, ok := index[col]; ok {
+						after[col] = args[j]
+					}
+				}
+				err = writeAuditRows(ctx, db, DBType, meta, AuditUpdate, before, after)
+			} else {
+				var key interface{}
+				if id != 0 {
+					key = id
+				}
+				err = writeAudit(ctx, db, DBType, table, AuditInsert, auditKey(insertedKey(meta, iargs, key)), nil, anyTMap(iargs))
+			}
+			if err != nil {
+				err = wrapError(fname, err)
+			}
+		}()
+	}
+
+	sq, strategy := GetDialect(DBType).Upsert(table, meta.quoteAll(DBType, columns), values, meta.quoteAll(DBType, conflictColumns), meta.quoteAll(DBType, updateColumns), idColumn)
+	if DEBUG {
+		log.Println(sq, args)
+	}
+
+	if strategy == QueryRowID && idColumn != "" {
+		var ID sql.NullInt64
+		err = db.QueryRowContext(ctx, sq, args...).Scan(&ID)
+		if err != nil {
+			return 0, 0, wrapError(currentFunction(), err)
+		}
+		return int(ID.Int64), 1, nil
+	}
+
+	res, err := db.ExecContext(ctx, sq, args...)
+	if err != nil {
+		return 0, 0, wrapError(currentFunction(), err)
+	}
+	ra, err := res.RowsAffected()
+	if err != nil {
+		return 0, 0, wrapError(currentFunction(), err)
+	}
+	rowsaff = int(ra)
+	if idColumn == "" {
+		return 0, rowsaff, nil
+	}
+
+	switch strategy {
+	case LastInsertID:
+		li, err := res.LastInsertId()
+		if err != nil {
+			return 0, rowsaff, wrapError(currentFunction(), err)
+		}
+		id = int(li)
+	case SelectID:
+		var ID sql.NullInt64
+		sq = "SELECT " + meta.quote(DBType, idColumn) + " FROM " + table + " WHERE " + strings.Join(where, " AND ")
+		if DEBUG {
+			log.Println(sq, conflictArgs)
+		}
+		err = db.QueryRowContext(ctx, sq, conflictArgs...).Scan(&ID)
+		if err != nil {
+			return 0, rowsaff, wrapError(currentFunction(), err)
+		}
+		id = int(ID.Int64)
+	}
+	return id, rowsaff, nil
+}
@@ -0,0 +1,46 @@
+package sqla
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+)
+
+func TestUpsertObject(t *testing.T) {
+	var DBType byte
+	DBType = SQLITE
+	var db *sql.DB
+	db = OpenSQLConnection(DBType, "file:upserttest?mode=memory&cache=shared")
+	defer db.Close()
+	db.Exec("CREATE TABLE settings (ID INTEGER PRIMARY KEY, Name TEXT NOT NULL UNIQUE, Value TEXT);")
+
+	var args AnyTslice
+	args = args.AppendNonEmptyString("Name", "theme")
+	args = args.AppendNonEmptyString("Value", "dark")
+	id, rowsaff, err := UpsertObjectContext(context.Background(), db, DBType, "settings", args, []string{"Name"}, nil)
+	if err != nil || id == 0 || rowsaff != 1 {
+		t.Errorf("Expected:%s, received:%d, %d, %v", "non-zero ID and 1 affected row", id, rowsaff, err)
+	}
+	args = nil
+	args = args.AppendNonEmptyString("Name", "theme")
+	args = args.AppendNonEmptyString("Value", "light")
+	id2, _ := UpsertObject(db, DBType, "settings", args, []string{"Name"}, []string{"Value"})
+	if id2 != id {
+		t.Errorf("Expected:%d, received:%d", id, id2)
+	}
+	var value string
+	db.QueryRow("SELECT Value FROM settings WHERE ID = $1", id).Scan(&value)
+	if value != "light" {
+		t.Errorf("Expected:%s, received:%s", "light", value)
+	}
+
+	args = nil
+	args = args.AppendInt("ID", id)
+	args = args.AppendNonEmptyString("Name", "palette")
+	if id2, _ = UpsertObject(db, DBType, "settings", args, nil, nil); id2 != id {
+		t.Errorf("Expected:%d, received:%d", id, id2)
+	}
+	if _, _, err = UpsertObjectContext(context.Background(), db, DBType, "settings", args, []string{"Value"}, nil); err == nil {
+		t.Errorf("Expected an error when conflict column value is not present, received nil")
+	}
+}